@@ -0,0 +1,93 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrEmptyAuthHeader = errors.New("auth header is empty")
+
+// AuthProvider provides the authorization header for the cloud and gateway APIs. It is consulted
+// on every dial of a WS endpoint and on every gRPC call, so a rotated secret is used from the
+// next reconnect or call on, without rebuilding the Client and losing its subscriptions.
+type AuthProvider interface {
+	AuthHeader(ctx context.Context) (string, error)
+}
+
+// AuthFunc is a function used as an AuthProvider, e.g. to fetch the header from a secret manager.
+// It must not block for long, as dials and calls wait for it.
+type AuthFunc func(ctx context.Context) (string, error)
+
+// AuthHeader calls f
+func (f AuthFunc) AuthHeader(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+type staticAuth string
+
+// NewStaticAuth returns an AuthProvider which always provides the given header
+func NewStaticAuth(header string) AuthProvider {
+	return staticAuth(header)
+}
+
+func (a staticAuth) AuthHeader(context.Context) (string, error) {
+	return string(a), nil
+}
+
+// NewAccountAuth returns an AuthProvider which provides the header of the account ID and secret
+func NewAccountAuth(accountID, secret string) AuthProvider {
+	return staticAuth(base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", accountID, secret))))
+}
+
+// fileAuth reads the header from a file, and again whenever the file is modified
+type fileAuth struct {
+	path    string
+	lock    *sync.Mutex
+	header  string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileAuth returns an AuthProvider which provides the header stored in the file, without the
+// surrounding whitespace. The file is read again when its modification time or size changes,
+// so it should be replaced with a rename rather than rewritten in place.
+func NewFileAuth(path string) AuthProvider {
+	return &fileAuth{path: path, lock: &sync.Mutex{}}
+}
+
+func (a *fileAuth) AuthHeader(context.Context) (string, error) {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat auth file: %w", err)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.header != "" && info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+		return a.header, nil
+	}
+
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read auth file: %w", err)
+	}
+
+	header := strings.TrimSpace(string(data))
+	if header == "" {
+		// the file may be in the middle of a rewrite, so it is read again on the next call
+		return "", fmt.Errorf("%w: %s", ErrEmptyAuthHeader, a.path)
+	}
+
+	a.header = header
+	a.modTime = info.ModTime()
+	a.size = info.Size()
+
+	return header, nil
+}
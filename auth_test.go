@@ -0,0 +1,142 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+// writeAuthFile replaces the auth file with a rename, as a secret rotation would
+func writeAuthFile(t *testing.T, path, header string) {
+	t.Helper()
+
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(header+"\n"), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestFileAuth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth")
+	auth := NewFileAuth(path)
+
+	_, err := auth.AuthHeader(context.Background())
+	require.Error(t, err)
+
+	writeAuthFile(t, path, "first")
+	header, err := auth.AuthHeader(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "first", header)
+
+	writeAuthFile(t, path, "second-header")
+	header, err = auth.AuthHeader(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "second-header", header)
+
+	writeAuthFile(t, path, "  ")
+	_, err = auth.AuthHeader(context.Background())
+	require.ErrorIs(t, err, ErrEmptyAuthHeader)
+}
+
+// testAuthGatewayServer records the authorization of each transaction request
+type testAuthGatewayServer struct {
+	testGatewayServer
+	authorization atomic.Value
+}
+
+func (s *testAuthGatewayServer) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization.Store(strings.Join(md.Get("authorization"), ","))
+
+	return s.testGatewayServer.BlxrTx(ctx, req)
+}
+
+func TestAuthRotation(t *testing.T) {
+	t.Run("ws", func(t *testing.T) {
+		headers := make(chan string, 10)
+		drop := make(chan struct{})
+
+		var connections atomic.Int32
+		upgrader := websocket.Upgrader{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			headers <- r.Header.Get(authHeaderKey)
+			if connections.Add(1) == 1 {
+				<-drop
+				return
+			}
+
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		path := filepath.Join(t.TempDir(), "auth")
+		writeAuthFile(t, path, "first")
+
+		c, err := NewClient(context.Background(), &Config{
+			WSGatewayURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+			AuthProvider: NewFileAuth(path),
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		require.Equal(t, "first", <-headers)
+
+		writeAuthFile(t, path, "second")
+		close(drop)
+
+		select {
+		case header := <-headers:
+			require.Equal(t, "second", header)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for reconnect")
+		}
+
+		require.Eventually(t, func() bool { return c.State() == StateConnected }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		srv := &testAuthGatewayServer{}
+
+		var header atomic.Value
+		header.Store("first")
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, srv),
+			AuthProvider: AuthFunc(func(context.Context) (string, error) {
+				return header.Load().(string), nil
+			}),
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		require.Equal(t, "first", srv.authorization.Load())
+
+		header.Store("second")
+		_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		require.Equal(t, "second", srv.authorization.Load())
+	})
+}
@@ -2,6 +2,7 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -15,7 +16,14 @@ type BdnBlockParams struct {
 }
 
 // OnBdnBlock subscribes to a stream of all new blocks as they are propagated in the BDN.
-func (c *Client) OnBdnBlock(ctx context.Context, params *BdnBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification]) error {
+func (c *Client) OnBdnBlock(ctx context.Context, params *BdnBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification], opts ...CallOption) error {
+	_, err := c.SubscribeBdnBlock(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribeBdnBlock subscribes to the BDN blocks feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribeBdnBlock(ctx context.Context, params *BdnBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
 		params = &BdnBlockParams{}
 	}
@@ -25,13 +33,33 @@ func (c *Client) OnBdnBlock(ctx context.Context, params *BdnBlockParams, callbac
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
+		if err != nil {
+			callbackFunc(ctx, err, nil)
+			return
+		}
 		callbackFunc(ctx, err, result.(*OnBdnBlockNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.BDNBlocksFeed, params, wrap)
+	return c.subscribe(ctx, types.BDNBlocksFeed, params, wrap, opts)
+}
+
+// OnBdnBlockChan subscribes to the BDN blocks feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnBdnBlockChan(ctx context.Context, params *BdnBlockParams, opts ...CallOption) (<-chan Notification[*OnBdnBlockNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBdnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeBdnBlock(ctx, params, callback, opts...)
+	})
+}
+
+// OnBdnBlockSeq returns an iterator over the notifications of the BDN blocks feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnBdnBlockSeq(ctx context.Context, params *BdnBlockParams, opts ...CallOption) iter.Seq2[*OnBdnBlockNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBdnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeBdnBlock(ctx, params, callback, opts...)
+	})
 }
 
-// UnsubscribeFromBdnBlock unsubscribes from the OnBdnBlock subscription.
+// UnsubscribeFromBdnBlock ends all OnBdnBlock subscriptions.
 func (c *Client) UnsubscribeFromBdnBlock() error {
 	return c.handler.UnsubscribeRetry(types.BDNBlocksFeed)
 }
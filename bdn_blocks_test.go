@@ -11,6 +11,7 @@ import (
 func TestOnBdnBlock(t *testing.T) {
 	t.Run("ws_cloud_api", testOnBdnBlock(wsCloudApiUrl))
 	t.Run("ws_gateway", testOnBdnBlock(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testOnBdnBlock(grpcCloudApiUrl))
 	t.Run("grpc_gateway", testOnBdnBlock(grpcGatewayUrl))
 }
 
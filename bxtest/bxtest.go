@@ -0,0 +1,396 @@
+// Package bxtest provides an in-process stand-in for the bloXroute cloud API and gateway, for tests which
+// must not depend on the network or on credentials. A Server answers WebSocket JSON-RPC requests and gRPC
+// Gateway calls on loopback, with scripted responses, errors and delays, and sends scripted notifications
+// to the subscriptions made to it. Disconnect drops the connections to test reconnects and resubscriptions.
+package bxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// JSON-RPC error codes of the responses of the Server
+const (
+	CodeInvalidParams  = -32602
+	CodeMethodNotFound = -32601
+	CodeInternalError  = -32603
+	CodeUnauthorized   = -32004
+)
+
+// ErrClosed is returned when the Server is closed
+var ErrClosed = errors.New("server is closed")
+
+// Request is a request received by the Server
+type Request struct {
+	Method jsonrpc.RPCRequestType
+
+	// Params are the JSON params of a WS request, or the gRPC request message in JSON.
+	// The params of a subscription are the feed followed by its params, over gRPC as well.
+	Params json.RawMessage
+
+	// GRPC is the gRPC request message, e.g. *pb.BlxrTxRequest, or nil for a WS request
+	GRPC any
+
+	// Header holds the headers of the WS handshake, or the metadata of the gRPC call
+	Header http.Header
+}
+
+// Error is the error of a response. Over WS, it is the JSON-RPC error of the response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+
+	// GRPCCode is the status code of the error over gRPC
+	// Optional (default: codes.Unknown)
+	GRPCCode codes.Code `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// Handler answers a request. Over WS, the result is the result of the JSON-RPC response. Over gRPC,
+// it is either the reply of the method, e.g. *pb.BlxrTxReply, or a value which has the JSON of the
+// WS result, e.g. {"tx_hash": "0x..."}, and is converted to the reply.
+type Handler func(ctx context.Context, req *Request) (any, error)
+
+// Subscription is a subscription made to the Server
+type Subscription struct {
+	// ID is the subscription ID assigned by the Server
+	ID   string
+	Feed types.FeedType
+
+	// Params are the JSON params of a WS subscription, or the gRPC request message in JSON
+	Params json.RawMessage
+
+	// GRPC is set for the subscriptions made with a gRPC stream
+	GRPC bool
+
+	// conn is the WS connection of the subscription
+	conn *wsConn
+	// send writes a notification to the subscriber
+	send func(any) error
+	// drop ends the subscription without unsubscribing
+	drop func()
+}
+
+// Server is a fake cloud API and gateway which listens on loopback for WS and gRPC connections
+type Server struct {
+	// WSURL is the URL of the WS endpoint, e.g. for Config.WSGatewayURL or Config.WSCloudAPIURL
+	WSURL string
+
+	// GRPCURL is the URL of the plain text gRPC endpoint, e.g. for Config.GRPCGatewayURL
+	GRPCURL string
+
+	pb.UnimplementedGatewayServer
+
+	ws         *httptest.Server
+	grpc       *grpc.Server
+	upgrader   websocket.Upgrader
+	authHeader string
+
+	lock          *sync.Mutex
+	handlers      map[jsonrpc.RPCRequestType]Handler
+	delays        map[jsonrpc.RPCRequestType]time.Duration
+	requests      []Request
+	subscriptions map[string]*Subscription
+	// subscribed is closed and replaced whenever a subscription is made
+	subscribed chan struct{}
+	conns      map[*wsConn]struct{}
+	nextID     int
+	closed     bool
+}
+
+// NewServer starts a Server. It panics if it can't listen on loopback, like httptest.NewServer.
+func NewServer() *Server {
+	s := &Server{
+		lock:          &sync.Mutex{},
+		handlers:      make(map[jsonrpc.RPCRequestType]Handler),
+		delays:        make(map[jsonrpc.RPCRequestType]time.Duration),
+		subscriptions: make(map[string]*Subscription),
+		subscribed:    make(chan struct{}),
+		conns:         make(map[*wsConn]struct{}),
+	}
+
+	s.ws = httptest.NewServer(http.HandlerFunc(s.serveWS))
+	s.WSURL = "ws" + strings.TrimPrefix(s.ws.URL, "http") + "/ws"
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		s.ws.Close()
+		panic(fmt.Sprintf("bxtest: failed to listen: %s", err))
+	}
+
+	s.grpc = grpc.NewServer()
+	pb.RegisterGatewayServer(s.grpc, s)
+	go func() { _ = s.grpc.Serve(listener) }()
+	s.GRPCURL = "grpc://" + listener.Addr().String()
+
+	return s
+}
+
+// Close drops all connections and stops the Server
+func (s *Server) Close() {
+	s.lock.Lock()
+	s.closed = true
+	s.lock.Unlock()
+
+	s.Disconnect()
+	s.grpc.Stop()
+	s.ws.Close()
+}
+
+// RequireAuth makes the Server reject the WS connections and the gRPC calls without the authorization header
+func (s *Server) RequireAuth(header string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.authHeader = header
+}
+
+// Handle sets the handler of the method, e.g. jsonrpc.RPCTx. A handler of jsonrpc.RPCSubscribe is called
+// before a subscription is made, and the subscription fails if it returns an error.
+// The requests of the methods without a handler fail with CodeMethodNotFound, or codes.Unimplemented over gRPC.
+func (s *Server) Handle(method jsonrpc.RPCRequestType, handler Handler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handlers[method] = handler
+}
+
+// Delay delays the responses to the requests of the method, including the subscriptions
+func (s *Server) Delay(method jsonrpc.RPCRequestType, delay time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.delays[method] = delay
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Subscriptions returns the active subscriptions to the feed
+func (s *Server) Subscriptions(feed types.FeedType) []Subscription {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var subscriptions []Subscription
+	for _, sub := range s.subscriptions {
+		if sub.Feed == feed {
+			subscriptions = append(subscriptions, *sub)
+		}
+	}
+
+	return subscriptions
+}
+
+// WaitSubscriptions waits until there are at least n active subscriptions to the feed
+func (s *Server) WaitSubscriptions(ctx context.Context, feed types.FeedType, n int) error {
+	for {
+		s.lock.Lock()
+		var count int
+		for _, sub := range s.subscriptions {
+			if sub.Feed == feed {
+				count++
+			}
+		}
+		subscribed := s.subscribed
+		s.lock.Unlock()
+
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d of %d subscriptions to %s: %w", count, n, feed, ctx.Err())
+		case <-subscribed:
+		}
+	}
+}
+
+// Notify sends the result in a notification to each WS subscription to the feed,
+// and returns the number of subscriptions it was sent to
+func (s *Server) Notify(feed types.FeedType, result any) int {
+	return s.notify(feed, false, result)
+}
+
+// NotifyGRPC sends the reply to each gRPC stream of the feed, and returns the number of streams it was sent to.
+// The reply must be the reply of the stream: *pb.TxsReply, *pb.BlocksReply or *pb.TxReceiptsReply.
+func (s *Server) NotifyGRPC(feed types.FeedType, reply any) int {
+	return s.notify(feed, true, reply)
+}
+
+// NotifySubscription sends the result to the subscription with the ID, e.g. the ID of the subscription
+// in the params of a request. The result of a gRPC subscription must be the reply of its stream.
+func (s *Server) NotifySubscription(id string, result any) error {
+	s.lock.Lock()
+	sub, ok := s.subscriptions[id]
+	s.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("subscription %s not found", id)
+	}
+
+	return sub.send(result)
+}
+
+func (s *Server) notify(feed types.FeedType, grpc bool, result any) int {
+	var sent int
+	for _, sub := range s.Subscriptions(feed) {
+		if sub.GRPC != grpc {
+			continue
+		}
+
+		if err := sub.send(result); err == nil {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+// Disconnect drops all WS connections and ends all gRPC streams with codes.Unavailable, as a restarting
+// gateway would. The subscriptions are dropped, and the clients are expected to reconnect and subscribe again.
+func (s *Server) Disconnect() {
+	s.lock.Lock()
+	conns := make([]*wsConn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	subscriptions := make([]*Subscription, 0, len(s.subscriptions))
+	for id, sub := range s.subscriptions {
+		subscriptions = append(subscriptions, sub)
+		delete(s.subscriptions, id)
+	}
+	s.lock.Unlock()
+
+	for _, sub := range subscriptions {
+		sub.drop()
+	}
+	for _, conn := range conns {
+		_ = conn.conn.Close()
+	}
+}
+
+// request records the request, waits for the delay of its method and calls its handler
+func (s *Server) request(ctx context.Context, req *Request) (any, error) {
+	s.lock.Lock()
+	s.requests = append(s.requests, *req)
+	handler, ok := s.handlers[req.Method]
+	delay := s.delays[req.Method]
+	s.lock.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if !ok {
+		if req.Method == jsonrpc.RPCSubscribe || req.Method == jsonrpc.RPCUnsubscribe {
+			return true, nil
+		}
+
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method %s not found", req.Method), GRPCCode: codes.Unimplemented}
+	}
+
+	return handler(ctx, req)
+}
+
+// addSubscription registers the subscription under a new ID
+func (s *Server) addSubscription(sub *Subscription) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.nextID++
+	sub.ID = fmt.Sprintf("subscription-%d", s.nextID)
+	s.subscriptions[sub.ID] = sub
+
+	close(s.subscribed)
+	s.subscribed = make(chan struct{})
+
+	return nil
+}
+
+// removeSubscription removes the subscription, and reports whether it was active
+func (s *Server) removeSubscription(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.subscriptions[id]
+	delete(s.subscriptions, id)
+
+	return ok
+}
+
+// authorized reports whether the authorization header is accepted
+func (s *Server) authorized(header http.Header) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.authHeader == "" || header.Get("Authorization") == s.authHeader
+}
+
+// metadataHeader returns the metadata of the gRPC call as headers
+func metadataHeader(ctx context.Context) http.Header {
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	return header
+}
+
+// grpcError converts the error of a handler to a gRPC status
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return status.Error(rpcErr.GRPCCode, rpcErr.Message)
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
@@ -0,0 +1,198 @@
+package bxtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/bxtest"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// transports are the configs of a client of a Server, over WS and over gRPC
+var transports = []struct {
+	name   string
+	config func(s *bxtest.Server) *sdk.Config
+	notify func(s *bxtest.Server, rawTx string) int
+}{
+	{
+		name: "ws",
+		config: func(s *bxtest.Server) *sdk.Config {
+			return &sdk.Config{WSGatewayURL: s.WSURL, AuthHeader: "auth"}
+		},
+		notify: func(s *bxtest.Server, rawTx string) int {
+			return s.Notify(types.NewTxsFeed, map[string]string{"rawTx": rawTx})
+		},
+	},
+	{
+		name: "grpc",
+		config: func(s *bxtest.Server) *sdk.Config {
+			return &sdk.Config{GRPCGatewayURL: s.GRPCURL, AuthHeader: "auth"}
+		},
+		notify: func(s *bxtest.Server, rawTx string) int {
+			return s.NotifyGRPC(types.NewTxsFeed, &pb.TxsReply{Tx: []*pb.Tx{{RawTx: []byte(rawTx)}}})
+		},
+	},
+}
+
+func testClient(t *testing.T, config *sdk.Config) *sdk.Client {
+	t.Helper()
+
+	c, err := sdk.NewClient(context.Background(), config)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, c.Close()) })
+
+	return c
+}
+
+func TestServerRequest(t *testing.T) {
+	for _, transport := range transports {
+		t.Run(transport.name, func(t *testing.T) {
+			s := bxtest.NewServer()
+			defer s.Close()
+			s.RequireAuth("auth")
+
+			s.Handle(jsonrpc.RPCTx, func(context.Context, *bxtest.Request) (any, error) {
+				return map[string]string{"tx_hash": "0x01"}, nil
+			})
+
+			c := testClient(t, transport.config(s))
+
+			reply, err := c.SendTx(context.Background(), &sdk.SendTxParams{Transaction: "f86b01"})
+			require.NoError(t, err)
+
+			var tx sdk.SendTxReply
+			require.NoError(t, json.Unmarshal(*reply, &tx))
+			require.Equal(t, "0x01", tx.TxHash)
+
+			requests := s.Requests()
+			require.Len(t, requests, 1)
+			require.Equal(t, jsonrpc.RPCTx, requests[0].Method)
+			require.Contains(t, string(requests[0].Params), "f86b01")
+			require.Equal(t, "auth", requests[0].Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestServerError(t *testing.T) {
+	s := bxtest.NewServer()
+	defer s.Close()
+
+	s.Handle(jsonrpc.RPCTx, func(context.Context, *bxtest.Request) (any, error) {
+		return nil, &bxtest.Error{Code: -32000, Message: "nonce too low", GRPCCode: codes.InvalidArgument}
+	})
+
+	t.Run("ws", func(t *testing.T) {
+		c := testClient(t, &sdk.Config{WSCloudAPIURL: s.WSURL, AuthHeader: "auth"})
+
+		_, err := c.SendTx(context.Background(), &sdk.SendTxParams{Transaction: "f86b01"})
+		var rpcErr *sdk.RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.EqualValues(t, -32000, rpcErr.Code)
+
+		// the methods without a handler aren't found
+		_, err = c.SendPrivateTx(context.Background(), &sdk.SendPrivateTxParams{Transaction: "f86b01"})
+		require.ErrorAs(t, err, &rpcErr)
+		require.EqualValues(t, bxtest.CodeMethodNotFound, rpcErr.Code)
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		c := testClient(t, transports[1].config(s))
+
+		_, err := c.SendTx(context.Background(), &sdk.SendTxParams{Transaction: "f86b01"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServerDelay(t *testing.T) {
+	for _, transport := range transports {
+		t.Run(transport.name, func(t *testing.T) {
+			s := bxtest.NewServer()
+			defer s.Close()
+
+			s.Handle(jsonrpc.RPCTx, func(context.Context, *bxtest.Request) (any, error) {
+				return map[string]string{"tx_hash": "0x01"}, nil
+			})
+			s.Delay(jsonrpc.RPCTx, time.Second)
+
+			c := testClient(t, transport.config(s))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			_, err := c.SendTx(ctx, &sdk.SendTxParams{Transaction: "f86b01"})
+			require.Error(t, err)
+			require.True(t, errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded, err)
+		})
+	}
+}
+
+func TestServerSubscription(t *testing.T) {
+	for _, transport := range transports {
+		t.Run(transport.name, func(t *testing.T) {
+			s := bxtest.NewServer()
+			defer s.Close()
+
+			config := transport.config(s)
+			resubscribed := make(chan struct{}, 1)
+			config.OnEvent = func(event sdk.Event) {
+				if event.Type == sdk.EventResubscribed {
+					select {
+					case resubscribed <- struct{}{}:
+					default:
+					}
+				}
+			}
+			c := testClient(t, config)
+
+			txs := make(chan string, 10)
+			_, err := c.SubscribeNewTx(context.Background(), nil, func(_ context.Context, err error, result *sdk.NewTxNotification) {
+				require.NoError(t, err)
+				txs <- result.RawTx
+			})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			require.NoError(t, s.WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+			require.Equal(t, 1, transport.notify(s, "0x01"))
+			require.Equal(t, "0x01", receive(t, txs))
+
+			// the client subscribes again after the server drops it
+			s.Disconnect()
+			require.Empty(t, s.Subscriptions(types.NewTxsFeed))
+			require.NoError(t, s.WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+
+			select {
+			case <-resubscribed:
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for the resubscription")
+			}
+
+			require.Equal(t, 1, transport.notify(s, "0x02"))
+			require.Equal(t, "0x02", receive(t, txs))
+		})
+	}
+}
+
+func receive(t *testing.T, txs <-chan string) string {
+	t.Helper()
+
+	select {
+	case tx := <-txs:
+		return tx
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for a notification")
+		return ""
+	}
+}
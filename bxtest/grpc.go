@@ -0,0 +1,233 @@
+package bxtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// the replies of the handlers which aren't gRPC replies are converted through the JSON of the WS results
+type (
+	txResult struct {
+		TxHash string `json:"tx_hash"`
+	}
+
+	batchTxResult struct {
+		TxHashes []struct {
+			Index  int32  `json:"idx"`
+			TxHash string `json:"tx_hash"`
+		} `json:"tx_hashes"`
+		TxErrors []struct {
+			Index int32  `json:"idx"`
+			Error string `json:"error"`
+		} `json:"tx_errors"`
+	}
+
+	bundleResult struct {
+		BundleHash string `json:"bundleHash"`
+	}
+)
+
+// BlxrTx answers with the handler of jsonrpc.RPCTx
+func (s *Server) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
+	result, err := s.requestGRPC(ctx, jsonrpc.RPCTx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply, ok := result.(*pb.BlxrTxReply); ok {
+		return reply, nil
+	}
+
+	var tx txResult
+	if err := convert(result, &tx); err != nil {
+		return nil, err
+	}
+
+	return &pb.BlxrTxReply{TxHash: tx.TxHash}, nil
+}
+
+// BlxrBatchTX answers with the handler of jsonrpc.RPCBatchTx
+func (s *Server) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (*pb.BlxrBatchTXReply, error) {
+	result, err := s.requestGRPC(ctx, jsonrpc.RPCBatchTx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply, ok := result.(*pb.BlxrBatchTXReply); ok {
+		return reply, nil
+	}
+
+	var batch batchTxResult
+	if err := convert(result, &batch); err != nil {
+		return nil, err
+	}
+
+	reply := &pb.BlxrBatchTXReply{}
+	for _, txHash := range batch.TxHashes {
+		reply.TxHashes = append(reply.TxHashes, &pb.TxIndex{Idx: txHash.Index, TxHash: txHash.TxHash})
+	}
+	for _, txErr := range batch.TxErrors {
+		reply.TxErrors = append(reply.TxErrors, &pb.ErrorIndex{Idx: txErr.Index, Error: txErr.Error})
+	}
+
+	return reply, nil
+}
+
+// BlxrSubmitBundle answers with the handler of jsonrpc.RPCBundleSubmission
+func (s *Server) BlxrSubmitBundle(ctx context.Context, req *pb.BlxrSubmitBundleRequest) (*pb.BlxrSubmitBundleReply, error) {
+	result, err := s.requestGRPC(ctx, jsonrpc.RPCBundleSubmission, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply, ok := result.(*pb.BlxrSubmitBundleReply); ok {
+		return reply, nil
+	}
+
+	var bundle bundleResult
+	if err := convert(result, &bundle); err != nil {
+		return nil, err
+	}
+
+	return &pb.BlxrSubmitBundleReply{BundleHash: bundle.BundleHash}, nil
+}
+
+// NewTxs streams the replies sent with NotifyGRPC(types.NewTxsFeed, ...)
+func (s *Server) NewTxs(req *pb.TxsRequest, stream pb.Gateway_NewTxsServer) error {
+	return serveStream[pb.TxsReply](s, types.NewTxsFeed, req, stream)
+}
+
+// PendingTxs streams the replies sent with NotifyGRPC(types.PendingTxsFeed, ...)
+func (s *Server) PendingTxs(req *pb.TxsRequest, stream pb.Gateway_PendingTxsServer) error {
+	return serveStream[pb.TxsReply](s, types.PendingTxsFeed, req, stream)
+}
+
+// NewBlocks streams the replies sent with NotifyGRPC(types.NewBlocksFeed, ...)
+func (s *Server) NewBlocks(req *pb.BlocksRequest, stream pb.Gateway_NewBlocksServer) error {
+	return serveStream[pb.BlocksReply](s, types.NewBlocksFeed, req, stream)
+}
+
+// BdnBlocks streams the replies sent with NotifyGRPC(types.BDNBlocksFeed, ...)
+func (s *Server) BdnBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnBlocksServer) error {
+	return serveStream[pb.BlocksReply](s, types.BDNBlocksFeed, req, stream)
+}
+
+// TxReceipts streams the replies sent with NotifyGRPC(types.TxReceiptsFeed, ...)
+func (s *Server) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_TxReceiptsServer) error {
+	return serveStream[pb.TxReceiptsReply](s, types.TxReceiptsFeed, req, stream)
+}
+
+// requestGRPC answers a unary call with the handler of the method
+func (s *Server) requestGRPC(ctx context.Context, method jsonrpc.RPCRequestType, req any) (any, error) {
+	header := metadataHeader(ctx)
+	if !s.authorized(header) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	params, err := json.Marshal(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to marshal request: %s", err)
+	}
+
+	result, err := s.request(ctx, &Request{Method: method, Params: params, GRPC: req, Header: header})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return result, nil
+}
+
+// replyStream is the server side of a stream of a feed
+type replyStream[Reply any] interface {
+	Send(*Reply) error
+	Context() context.Context
+}
+
+// serveStream makes a subscription of the stream, and sends it the notifications of the feed until
+// the client ends the stream or the subscription is dropped
+func serveStream[Reply any](s *Server, feed types.FeedType, req any, stream replyStream[Reply]) error {
+	ctx := stream.Context()
+	header := metadataHeader(ctx)
+	if !s.authorized(header) {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	// the params are those of a WS subscription, the feed followed by the request
+	params, err := json.Marshal([]any{feed, req})
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to marshal request: %s", err)
+	}
+
+	if _, err := s.request(ctx, &Request{Method: jsonrpc.RPCSubscribe, Params: params, GRPC: req, Header: header}); err != nil {
+		return grpcError(err)
+	}
+
+	request, _ := json.Marshal(req)
+	dropped := make(chan struct{})
+	dropOnce := &sync.Once{}
+	sendLock := &sync.Mutex{}
+
+	sub := &Subscription{Feed: feed, Params: request, GRPC: true}
+	sub.send = func(v any) error {
+		reply, ok := v.(*Reply)
+		if !ok {
+			return fmt.Errorf("expected a %T reply for the %s stream, got %T", new(Reply), feed, v)
+		}
+
+		sendLock.Lock()
+		defer sendLock.Unlock()
+
+		select {
+		case <-dropped:
+			return status.Error(codes.Unavailable, "stream dropped")
+		default:
+		}
+
+		return stream.Send(reply)
+	}
+	sub.drop = func() {
+		dropOnce.Do(func() { close(dropped) })
+	}
+
+	if err := s.addSubscription(sub); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer s.removeSubscription(sub.ID)
+
+	select {
+	case <-ctx.Done():
+		err = status.FromContextError(ctx.Err()).Err()
+	case <-dropped:
+		err = status.Error(codes.Unavailable, "stream dropped")
+	}
+
+	// no more replies are sent once the handler returns
+	sendLock.Lock()
+	sub.drop()
+	sendLock.Unlock()
+
+	return err
+}
+
+// convert converts the result of a handler to a reply through JSON
+func convert(result any, reply any) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal result: %s", err)
+	}
+
+	if err := json.Unmarshal(b, reply); err != nil {
+		return status.Errorf(codes.Internal, "failed to convert result %s to %T: %s", b, reply, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package bxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/fasthttp/websocket"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// wsConn is a WS connection to the Server, whose writes are serialized
+type wsConn struct {
+	conn   *websocket.Conn
+	header http.Header
+	lock   *sync.Mutex
+}
+
+func (c *wsConn) write(v any) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.conn.WriteJSON(v)
+}
+
+type wsRequest struct {
+	ID     json.RawMessage        `json:"id"`
+	Method jsonrpc.RPCRequestType `json:"method"`
+	Params json.RawMessage        `json:"params"`
+}
+
+type wsResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type wsNotification struct {
+	JSONRPC string                   `json:"jsonrpc"`
+	Method  jsonrpc.RPCRequestType   `json:"method"`
+	Params  wsNotificationParameters `json:"params"`
+}
+
+type wsNotificationParameters struct {
+	Subscription string `json:"subscription"`
+	Result       any    `json:"result"`
+}
+
+// serveWS upgrades the connection and answers its requests until it is closed
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r.Header) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &wsConn{conn: conn, header: r.Header.Clone(), lock: &sync.Mutex{}}
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		_ = conn.Close()
+		return
+	}
+	s.conns[c] = struct{}{}
+	s.lock.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	defer func() {
+		cancel()
+		_ = conn.Close()
+		wg.Wait()
+
+		s.lock.Lock()
+		delete(s.conns, c)
+		for id, sub := range s.subscriptions {
+			if sub.conn == c {
+				delete(s.subscriptions, id)
+			}
+		}
+		s.lock.Unlock()
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		req := &wsRequest{}
+		if err := json.Unmarshal(message, req); err != nil || len(req.ID) == 0 {
+			continue
+		}
+
+		// the requests are answered concurrently, so that a delayed response doesn't hold back the others
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleWS(ctx, c, req)
+		}()
+	}
+}
+
+// handleWS answers a request of the connection
+func (s *Server) handleWS(ctx context.Context, c *wsConn, req *wsRequest) {
+	request := &Request{Method: req.Method, Params: req.Params, Header: c.header}
+
+	switch req.Method {
+	case jsonrpc.RPCSubscribe:
+		s.subscribeWS(ctx, c, req, request)
+		return
+	case jsonrpc.RPCUnsubscribe:
+		var params []string
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 1 {
+			_ = c.write(errorResponse(req.ID, &Error{Code: CodeInvalidParams, Message: "expected the subscription ID"}))
+			return
+		}
+
+		result, err := s.request(ctx, request)
+		if err != nil {
+			_ = c.write(errorResponse(req.ID, err))
+			return
+		}
+
+		s.removeSubscription(params[0])
+		_ = c.write(&wsResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+		return
+	}
+
+	result, err := s.request(ctx, request)
+	if err != nil {
+		_ = c.write(errorResponse(req.ID, err))
+		return
+	}
+
+	_ = c.write(&wsResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+// subscribeWS makes the subscription and answers with its ID
+func (s *Server) subscribeWS(ctx context.Context, c *wsConn, req *wsRequest, request *Request) {
+	var params []json.RawMessage
+	var feed types.FeedType
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 || json.Unmarshal(params[0], &feed) != nil {
+		_ = c.write(errorResponse(req.ID, &Error{Code: CodeInvalidParams, Message: "expected the feed name"}))
+		return
+	}
+
+	if _, err := s.request(ctx, request); err != nil {
+		_ = c.write(errorResponse(req.ID, err))
+		return
+	}
+
+	sub := &Subscription{Feed: feed, conn: c}
+	if len(params) > 1 {
+		sub.Params = params[1]
+	}
+	sub.send = func(result any) error {
+		return c.write(&wsNotification{
+			JSONRPC: "2.0",
+			Method:  jsonrpc.RPCSubscribe,
+			Params:  wsNotificationParameters{Subscription: sub.ID, Result: result},
+		})
+	}
+	sub.drop = func() {
+		_ = c.conn.Close()
+	}
+
+	// the response is written before any notification of the subscription can be
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := s.addSubscription(sub); err != nil {
+		_ = c.conn.WriteJSON(errorResponse(req.ID, err))
+		return
+	}
+
+	_ = c.conn.WriteJSON(&wsResponse{JSONRPC: "2.0", ID: req.ID, Result: sub.ID})
+}
+
+// errorResponse returns the response of a failed request
+func errorResponse(id json.RawMessage, err error) *wsResponse {
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) {
+		rpcErr = &Error{Code: CodeInternalError, Message: fmt.Sprintf("request failed: %s", err)}
+	}
+
+	return &wsResponse{JSONRPC: "2.0", ID: id, Error: rpcErr}
+}
@@ -0,0 +1,213 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/connection/ws"
+)
+
+const defaultRetryInitialInterval = time.Millisecond * 50
+
+// Priority is the priority of a call
+type Priority int
+
+// Priority enumeration
+const (
+	// PriorityNormal is the priority of calls by default
+	PriorityNormal Priority = iota
+	// PriorityHigh calls are written to a WS connection ahead of the normal priority calls waiting to be written
+	PriorityHigh
+)
+
+// RetryPolicy is how a call is retried when it fails because of the connection,
+// e.g. with ErrConnectionLost or ErrRequestTimeout. Errors returned by the endpoint aren't retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one
+	MaxAttempts int
+
+	// InitialInterval is the wait before the first retry, which grows exponentially for the following ones
+	// Optional (default: 50ms)
+	InitialInterval time.Duration
+
+	// MaxInterval caps the wait between retries
+	// Optional (default: no cap)
+	MaxInterval time.Duration
+}
+
+// CallOption configures a single call of a Client method, or all calls when set in Config.CallOptions
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout time.Duration
+	// deadline is derived from timeout when the call starts, and applies to all its attempts
+	deadline time.Time
+	retry    *RetryPolicy
+	endpoint string
+	priority Priority
+}
+
+// WithTimeout limits how long the call may take, including retries. For subscriptions, it limits
+// how long subscribing may take, not the subscription itself.
+// Without it, a request waits for its response for Config.RequestTimeout.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetry retries the call according to the policy
+func WithRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithEndpoint sends the request through the endpoint with the given URL instead of the active one,
+// if the client is connected to it (see Config.ConnectAllEndpoints). It doesn't apply to subscriptions.
+func WithEndpoint(url string) CallOption {
+	return func(o *callOptions) {
+		o.endpoint = url
+	}
+}
+
+// WithPriority sets the priority of the call
+func WithPriority(priority Priority) CallOption {
+	return func(o *callOptions) {
+		o.priority = priority
+	}
+}
+
+type callOptionsKey struct{}
+
+// withCallOptions applies Config.CallOptions and then opts, and passes the result to the handlers with the context
+func (c *Client) withCallOptions(ctx context.Context, opts []CallOption) (context.Context, *callOptions) {
+	o := &callOptions{}
+	for _, opt := range c.config.CallOptions {
+		opt(o)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.timeout > 0 {
+		o.deadline = time.Now().Add(o.timeout)
+	}
+
+	return context.WithValue(ctx, callOptionsKey{}, o), o
+}
+
+// withDeadline returns ctx limited by the deadline of the call, if it has one
+func (o *callOptions) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.deadline.IsZero() {
+		return ctx, func() {}
+	}
+
+	return context.WithDeadline(ctx, o.deadline)
+}
+
+// callOptionsFromContext returns the options of the call made with ctx
+func callOptionsFromContext(ctx context.Context) *callOptions {
+	o, ok := ctx.Value(callOptionsKey{}).(*callOptions)
+	if !ok {
+		return &callOptions{}
+	}
+
+	return o
+}
+
+// request sends the request through the handler according to the call options
+func (c *Client) request(ctx context.Context, h handler, method jsonrpc.RPCRequestType, params any, opts []CallOption) (res *json.RawMessage, err error) {
+	ctx, span := c.startSpan(ctx, method)
+	defer func() { endSpan(span, err) }()
+
+	ctx, o := c.withCallOptions(ctx, opts)
+	ctx, cancel := o.withDeadline(ctx)
+	defer cancel()
+
+	if err := c.limiter.request(ctx, method); err != nil {
+		return nil, err
+	}
+
+	err = o.do(ctx, func() error {
+		var err error
+		res, err = h.Request(ctx, method, params)
+		return err
+	})
+
+	return res, err
+}
+
+// do calls fn, and again according to the retry policy while it fails with a retryable error,
+// until the deadline of the call
+func (o *callOptions) do(ctx context.Context, fn func() error) error {
+	if o.retry == nil || o.retry.MaxAttempts <= 1 {
+		return fn()
+	}
+
+	backOff := backoff.NewExponentialBackOff()
+	backOff.InitialInterval = defaultRetryInitialInterval
+	if o.retry.InitialInterval > 0 {
+		backOff.InitialInterval = o.retry.InitialInterval
+	}
+	if o.retry.MaxInterval > 0 {
+		backOff.MaxInterval = o.retry.MaxInterval
+	}
+	// the attempts are limited by MaxAttempts and ctx only
+	backOff.MaxElapsedTime = 0
+
+	// fn isn't given retryCtx, as a subscription outlives the call
+	retryCtx, cancel := o.withDeadline(ctx)
+	defer cancel()
+
+	var lastErr error
+	retried := func() error {
+		lastErr = fn()
+		if lastErr != nil && !retryable(lastErr) {
+			return backoff.Permanent(lastErr)
+		}
+
+		return lastErr
+	}
+
+	err := backoff.Retry(retried, backoff.WithContext(backoff.WithMaxRetries(backOff, uint64(o.retry.MaxAttempts-1)), retryCtx))
+	if err != nil && ctx.Err() == nil && retryCtx.Err() != nil && lastErr != nil {
+		// the deadline passed between the attempts, so the failure of the last one is returned
+		return lastErr
+	}
+
+	return err
+}
+
+// retryable reports whether the call failed because of the connection rather than the endpoint
+func retryable(err error) bool {
+	return errors.Is(err, ErrConnectionLost) ||
+		errors.Is(err, ErrRequestTimeout) ||
+		errors.Is(err, ErrNotConnected) ||
+		errors.Is(err, ErrNoResponse) ||
+		errors.Is(err, ws.ErrAlreadyClosed)
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent marks the requests made with the returned context as idempotent. A WS request
+// which is idempotent is sent again after a reconnect, instead of failing with ConnectionLostError
+// when the connection is lost before its response arrives.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent reports whether the request can be sent again without side effects,
+// either because it was marked with WithIdempotent or because it only reads data
+func isIdempotent(ctx context.Context, method jsonrpc.RPCRequestType) bool {
+	if idempotent, _ := ctx.Value(idempotentKey{}).(bool); idempotent {
+		return true
+	}
+
+	return method == RPCBSCGetBundlePrice || method == jsonrpc.RPCQuotaUsage
+}
@@ -0,0 +1,118 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testFlakyHandler fails the first requests with err, then responds with the result of testRequestHandler
+type testFlakyHandler struct {
+	testRequestHandler
+	failures int32
+	attempts atomic.Int32
+}
+
+func (h *testFlakyHandler) Request(ctx context.Context, method jsonrpc.RPCRequestType, params any) (*json.RawMessage, error) {
+	if h.attempts.Add(1) <= h.failures {
+		return nil, h.err
+	}
+
+	return (&testRequestHandler{result: h.result}).Request(ctx, method, params)
+}
+
+func (h *testFlakyHandler) Subscribe(context.Context, string, types.FeedType, any, CallbackFunc[any]) error {
+	if h.attempts.Add(1) <= h.failures {
+		return h.err
+	}
+
+	return nil
+}
+
+func TestCallOptions(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		h := &testFlakyHandler{
+			testRequestHandler: testRequestHandler{result: `{"tx_hash":"0x1"}`, err: &ConnectionLostError{Endpoint: "a", Err: errors.New("EOF")}},
+			failures:           2,
+		}
+		c := &Client{handler: h, config: &Config{}}
+
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.ErrorIs(t, err, ErrConnectionLost)
+		require.EqualValues(t, 1, h.attempts.Load())
+
+		h.attempts.Store(0)
+		res, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"}, WithRetry(RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond}))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"tx_hash":"0x1"}`, string(*res))
+		require.EqualValues(t, 3, h.attempts.Load())
+	})
+
+	t.Run("no_retry_on_endpoint_error", func(t *testing.T) {
+		h := &testFlakyHandler{
+			testRequestHandler: testRequestHandler{result: `{"tx_hash":"0x1"}`, err: errors.New("rejected")},
+			failures:           1,
+		}
+		c := &Client{handler: h, config: &Config{CallOptions: []CallOption{WithRetry(RetryPolicy{MaxAttempts: 3})}}}
+
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.EqualError(t, err, "rejected")
+		require.EqualValues(t, 1, h.attempts.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := &Client{handler: &testRequestHandler{result: `{"tx_hash":"0x1"}`, delay: time.Second}, config: &Config{}}
+
+		start := time.Now()
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"}, WithTimeout(10*time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("timeout_with_retry", func(t *testing.T) {
+		h := &testFlakyHandler{
+			testRequestHandler: testRequestHandler{err: &ConnectionLostError{Endpoint: "a", Err: errors.New("EOF")}},
+			failures:           math.MaxInt32,
+		}
+		c := &Client{handler: h, config: &Config{}}
+		opts := []CallOption{
+			WithTimeout(50 * time.Millisecond),
+			WithRetry(RetryPolicy{MaxAttempts: math.MaxInt32, InitialInterval: 5 * time.Millisecond, MaxInterval: 5 * time.Millisecond}),
+		}
+
+		// the timeout covers all the attempts of the subscription, not each of them
+		start := time.Now()
+		_, err := c.subscribe(context.Background(), types.NewTxsFeed, nil, nil, opts)
+		require.ErrorIs(t, err, ErrConnectionLost)
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("endpoint", func(t *testing.T) {
+		c := testFanOutClient(
+			&testRequestHandler{result: `{"tx_hash":"0xa"}`},
+			&testRequestHandler{result: `{"tx_hash":"0xb"}`},
+		)
+
+		res, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"tx_hash":"0xa"}`, string(*res))
+
+		res, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"}, WithEndpoint("b"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"tx_hash":"0xb"}`, string(*res))
+
+		// an unknown endpoint falls back to the active one
+		res, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"}, WithEndpoint("c"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"tx_hash":"0xa"}`, string(*res))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"runtime"
 	"strings"
 	"sync"
@@ -22,10 +23,31 @@ type handlerSourceType int
 const (
 	handlerSourceTypeCloudAPIWS handlerSourceType = iota
 	handlerSourceTypeGatewayWS
-	handlerSourceTypeCloudAPIGRPC // not yet supported
+	handlerSourceTypeCloudAPIGRPC
 	handlerSourceTypeGatewayGRPC
 )
 
+// String returns the name of the handler type used in the spans of its requests
+func (t handlerSourceType) String() string {
+	switch t {
+	case handlerSourceTypeCloudAPIWS:
+		return "cloud_api_ws"
+	case handlerSourceTypeGatewayWS:
+		return "gateway_ws"
+	case handlerSourceTypeCloudAPIGRPC:
+		return "cloud_api_grpc"
+	case handlerSourceTypeGatewayGRPC:
+		return "gateway_grpc"
+	default:
+		return "unknown"
+	}
+}
+
+// isGRPC reports whether the handler talks to its endpoint over gRPC
+func (t handlerSourceType) isGRPC() bool {
+	return t == handlerSourceTypeCloudAPIGRPC || t == handlerSourceTypeGatewayGRPC
+}
+
 var (
 	ErrClientNotInitialized = errors.New("client not initialized")
 	ErrNilParams            = errors.New("params cannot be nil")
@@ -33,15 +55,22 @@ var (
 
 type handler interface {
 	Type() handlerSourceType
-	Subscribe(ctx context.Context, f types.FeedType, req any, callback CallbackFunc[any]) error
+	Subscribe(ctx context.Context, id string, f types.FeedType, req any, callback CallbackFunc[any]) error
 	Request(ctx context.Context, method jsonrpc.RPCRequestType, params any) (*json.RawMessage, error)
+	Unsubscribe(id string) error
 	UnsubscribeRetry(f types.FeedType) error
+	Healthy() bool
+	State() State
+	DispatchStats() []DispatchStats
 	Close() error
 }
 
 // Client is a client for the bloXroute cloud API.
 type Client struct {
 	handler           handler
+	config            *Config
+	limiter           *rateLimiter
+	endpoint          string
 	blockchainNetwork string
 	initialized       bool
 }
@@ -58,6 +87,8 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 	config.setDefaults()
 
 	c := &Client{
+		config:            config,
+		limiter:           newRateLimiter(config.RateLimits),
 		blockchainNetwork: config.BlockchainNetwork,
 	}
 
@@ -80,61 +111,141 @@ func (c *Client) Close() (err error) {
 	return c.handler.Close()
 }
 
+// State returns the state of the connection to the active endpoint
+func (c *Client) State() State {
+	if !c.initialized {
+		return StateConnecting
+	}
+
+	return c.handler.State()
+}
+
+// DispatchStats returns the dispatch queue stats of all active subscriptions,
+// including the number of notifications dropped because of the overflow policy
+func (c *Client) DispatchStats() ([]DispatchStats, error) {
+	if !c.initialized {
+		return nil, ErrClientNotInitialized
+	}
+
+	return c.handler.DispatchStats(), nil
+}
+
 func (c *Client) connect(ctx context.Context, config *Config) error {
-	if config.GRPCGatewayURL != "" {
-		grpcConn, err := grpc.NewClient(strings.TrimPrefix(config.GRPCGatewayURL, "grpc://"), config.GRPCDialOptions...)
+	c.endpoint = config.Endpoints[0].String()
+
+	if len(config.Endpoints) > 1 {
+		h, err := newMultiHandler(ctx, config)
 		if err != nil {
-			return fmt.Errorf("failed to create GRPC connection: %w", err)
+			return err
 		}
 
-		c.handler = &grpcHandler{
-			hst:    handlerSourceTypeGatewayGRPC,
-			config: config,
-			conn:   grpcConn,
-			client: pb.NewGatewayClient(grpcConn),
-			md: metadata.New(map[string]string{
-				blockchainHeaderKey: config.BlockchainNetwork,
-				sdkVersionHeaderKey: buildVersion,
-				languageHeaderKey:   runtime.Version(),
-			}),
-			stop:          make(chan struct{}),
-			wg:            &sync.WaitGroup{},
-			subscriptions: make(map[types.FeedType]grpcSubscription),
-			lock:          &sync.Mutex{},
-		}
+		c.handler = h
 
 		return nil
 	}
 
-	var hst handlerSourceType
-	if config.WSCloudAPIURL != "" {
-		hst = handlerSourceTypeCloudAPIWS
-	} else {
-		hst = handlerSourceTypeGatewayWS
+	h, err := newHandler(ctx, config.Endpoints[0], config)
+	if err != nil {
+		return err
+	}
+
+	c.handler = h
+
+	return nil
+}
+
+// newHandler creates a connected handler for the given endpoint
+func newHandler(ctx context.Context, endpoint Endpoint, config *Config) (handler, error) {
+	switch {
+	case endpoint.GRPCGatewayURL != "":
+		return newGRPCHandler(handlerSourceTypeGatewayGRPC, endpoint.GRPCGatewayURL, config)
+	case endpoint.GRPCCloudAPIURL != "":
+		return newGRPCHandler(handlerSourceTypeCloudAPIGRPC, endpoint.GRPCCloudAPIURL, config)
+	case endpoint.WSCloudAPIURL != "":
+		return newWSHandler(ctx, handlerSourceTypeCloudAPIWS, endpoint.WSCloudAPIURL, config)
+	default:
+		return newWSHandler(ctx, handlerSourceTypeGatewayWS, endpoint.WSGatewayURL, config)
 	}
+}
+
+func newWSHandler(ctx context.Context, hst handlerSourceType, url string, config *Config) (*wsHandler, error) {
+	ctx, cancel := context.WithCancel(ctx)
 
 	h := &wsHandler{
 		hst:             hst,
+		url:             url,
 		config:          config,
-		feeds:           make(map[types.FeedType]feed),
-		subscriptions:   make(map[string]wsSubscription),
-		pendingResponse: make(map[jsonrpc2.ID]chan requestResponse),
+		logger:          config.Slog.With(slog.String(logKeyEndpoint, url), slog.String(logKeyHandler, hst.String())),
+		subscriptions:   make(map[string]*wsSubscription),
+		serverIDs:       make(map[string]string),
+		pendingResponse: make(map[jsonrpc2.ID]*pendingRequest),
 		lock:            &sync.Mutex{},
 		stop:            make(chan struct{}),
+		cancel:          cancel,
 		wg:              &sync.WaitGroup{},
 		readErr:         make(chan error, 1),
 	}
 
 	err := h.reconnect(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to connect to WS: %w", err)
+		cancel()
+		return nil, fmt.Errorf("failed to connect to WS: %w", err)
 	}
 
+	h.setState(StateConnected)
+	config.emit(Event{Type: EventConnected, Endpoint: url})
+
 	h.wg.Add(1)
 
 	go h.read(ctx)
 
-	c.handler = h
+	return h, nil
+}
 
-	return nil
+func newGRPCHandler(hst handlerSourceType, url string, config *Config) (*grpcHandler, error) {
+	target := grpcTarget(url)
+	if config.Proxy != nil {
+		// the dialer gets the host name instead of its addresses, which may not resolve without the proxy
+		target = "passthrough:///" + target
+	}
+
+	grpcConn, err := grpc.NewClient(target, config.grpcDialOptions(hst, url)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GRPC connection: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h := &grpcHandler{
+		hst:    hst,
+		url:    url,
+		config: config,
+		logger: config.Slog.With(slog.String(logKeyEndpoint, url), slog.String(logKeyHandler, hst.String())),
+		conn:   grpcConn,
+		client: pb.NewGatewayClient(grpcConn),
+		md: metadata.New(map[string]string{
+			blockchainHeaderKey: config.BlockchainNetwork,
+			sdkVersionHeaderKey: buildVersion,
+			languageHeaderKey:   runtime.Version(),
+		}),
+		stop:          make(chan struct{}),
+		cancel:        cancel,
+		wg:            &sync.WaitGroup{},
+		subscriptions: make(map[string]grpcSubscription),
+		lock:          &sync.Mutex{},
+	}
+
+	h.wg.Add(1)
+	go h.watchState(ctx)
+
+	return h, nil
+}
+
+// grpcTarget strips the URL scheme since gRPC expects a plain host:port target
+func grpcTarget(url string) string {
+	for _, scheme := range []string{"grpcs://", "grpc://"} {
+		url = strings.TrimPrefix(url, scheme)
+	}
+
+	return url
 }
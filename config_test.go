@@ -2,23 +2,43 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"encoding/hex"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 type testURL string
 
 const (
-	wsCloudApiUrl  testURL = "WS_CLOUD_API_URL"
-	wsGatewayUrl   testURL = "WS_GATEWAY_URL"
-	grpcGatewayUrl testURL = "GRPC_GATEWAY_URL"
+	wsCloudApiUrl   testURL = "WS_CLOUD_API_URL"
+	wsGatewayUrl    testURL = "WS_GATEWAY_URL"
+	grpcCloudApiUrl testURL = "GRPC_CLOUD_API_URL"
+	grpcGatewayUrl  testURL = "GRPC_GATEWAY_URL"
 )
 
+// offline runs the endpoint tests against the in-process stand-ins, even if the endpoints are configured
+var offline = flag.Bool("offline", false, "run the endpoint tests against in-process stand-ins")
+
+// testLive reports whether the tests of the URL run against its endpoint rather than its in-process stand-in
+func testLive(url testURL) bool {
+	return !*offline && os.Getenv(string(url)) != ""
+}
+
+// testConfig returns the config of the endpoint of the URL, or of its in-process stand-in
+// when the endpoint isn't configured or the tests run with -offline
 func testConfig(t *testing.T, url testURL) *Config {
 	t.Helper()
 
+	if !testLive(url) {
+		config, _ := testStandIn(t, url)
+		return config
+	}
+
 	c := &Config{
 		AuthHeader: os.Getenv("AUTH_HEADER"),
 	}
@@ -27,6 +47,8 @@ func testConfig(t *testing.T, url testURL) *Config {
 		c.WSCloudAPIURL = os.Getenv(string(wsCloudApiUrl))
 	case wsGatewayUrl:
 		c.WSGatewayURL = os.Getenv(string(wsGatewayUrl))
+	case grpcCloudApiUrl:
+		c.GRPCCloudAPIURL = os.Getenv(string(grpcCloudApiUrl))
 	case grpcGatewayUrl:
 		c.GRPCGatewayURL = os.Getenv(string(grpcGatewayUrl))
 	default:
@@ -36,6 +58,39 @@ func testConfig(t *testing.T, url testURL) *Config {
 	return c
 }
 
+// testEndpointsConfig returns the config of a client of the endpoints of the URLs, in order
+func testEndpointsConfig(t *testing.T, urls ...testURL) *Config {
+	t.Helper()
+
+	c := &Config{}
+	for _, url := range urls {
+		config := testConfig(t, url)
+		c.AuthHeader = config.AuthHeader
+		c.Endpoints = append(c.Endpoints, Endpoint{
+			WSCloudAPIURL:   config.WSCloudAPIURL,
+			WSGatewayURL:    config.WSGatewayURL,
+			GRPCCloudAPIURL: config.GRPCCloudAPIURL,
+			GRPCGatewayURL:  config.GRPCGatewayURL,
+		})
+	}
+
+	return c
+}
+
+// testTxBytes returns the transaction to send, from the environment or signed for the stand-ins
+func testTxBytes(t *testing.T) string {
+	t.Helper()
+
+	if txBytes := os.Getenv("TX_BYTES"); txBytes != "" {
+		return txBytes
+	}
+
+	raw, err := standInTx(0).MarshalBinary()
+	require.NoError(t, err)
+
+	return hex.EncodeToString(raw)
+}
+
 // contextWithSignal returns a context that is cancelled when the process receives the given termination signal.
 func contextWithSignal(parent context.Context, s ...os.Signal) context.Context {
 	if len(s) == 0 {
@@ -0,0 +1,767 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/bxtest"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// standInAuthHeader is the authorization header the stand-ins require
+const standInAuthHeader = "stand-in"
+
+// standInInterval is how often the stand-ins send the notifications of their feeds
+const standInInterval = 10 * time.Millisecond
+
+// standInKey signs the transactions of the stand-ins, which all stream the same transactions in the same order
+var standInKey, _ = crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+
+// standInCallResponses are the eth_onBlock responses of the stand-ins, as the live tests expect them
+var standInCallResponses = map[string]string{
+	"eth_call":                "0x",
+	"eth_getBalance":          "0x23708c8bd551c12",
+	"eth_getTransactionCount": "0x96",
+	"eth_getCode":             "0x",
+	"eth_getStorageAt":        "0x" + strings.Repeat("0", 64),
+	"eth_blockNumber":         "0x13a5c2f",
+}
+
+// standInTx returns the transaction of the stand-ins with the nonce
+func standInTx(nonce uint64) *ethtypes.Transaction {
+	to := common.HexToAddress("0xCbe321c620071307Ba5d0381c886B7359763735E")
+	tx, err := ethtypes.SignNewTx(standInKey, ethtypes.LatestSignerForChainID(big.NewInt(1)), &ethtypes.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Value:    big.NewInt(1),
+		Gas:      21000,
+		GasPrice: big.NewInt(params.GWei),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return tx
+}
+
+// standInTxHash returns the hash of a raw transaction, with or without the 0x prefix
+func standInTxHash(rawTx string) string {
+	return crypto.Keccak256Hash(common.FromHex(rawTx)).Hex()
+}
+
+// standInNotification returns the result of the recorded notification of the feed in testdata
+func standInNotification(t *testing.T, feed types.FeedType) json.RawMessage {
+	t.Helper()
+
+	var notification struct {
+		Params struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(testNotificationFrame(t, feed), &notification))
+	require.NotEmpty(t, notification.Params.Result)
+
+	return notification.Params.Result
+}
+
+// testStandIn starts an in-process stand-in for the endpoint of the URL and returns the config of a client of it.
+// The stand-in answers the requests of the SDK and streams notifications to every subscription of every feed.
+func testStandIn(t *testing.T, url testURL) (*Config, *bxtest.Server) {
+	t.Helper()
+
+	s := bxtest.NewServer()
+	t.Cleanup(s.Close)
+	s.RequireAuth(standInAuthHeader)
+
+	handleStandInRequests(s)
+	streamStandInFeeds(t, s)
+
+	config := &Config{AuthHeader: standInAuthHeader}
+	switch url {
+	case wsCloudApiUrl:
+		config.WSCloudAPIURL = s.WSURL
+	case wsGatewayUrl:
+		config.WSGatewayURL = s.WSURL
+	case grpcCloudApiUrl:
+		config.GRPCCloudAPIURL = s.GRPCURL
+	case grpcGatewayUrl:
+		config.GRPCGatewayURL = s.GRPCURL
+	default:
+		t.Fatalf("unknown test url: %s", url)
+	}
+
+	return config, s
+}
+
+// handleStandInRequests answers the requests of the SDK, over WS and gRPC
+func handleStandInRequests(s *bxtest.Server) {
+	invalidParams := func(err error) error {
+		return &bxtest.Error{Code: bxtest.CodeInvalidParams, Message: err.Error(), GRPCCode: codes.InvalidArgument}
+	}
+
+	sendTx := func(_ context.Context, req *bxtest.Request) (any, error) {
+		params := &SendTxParams{}
+		if grpcReq, ok := req.GRPC.(*pb.BlxrTxRequest); ok {
+			params.Transaction = grpcReq.Transaction
+		} else if err := json.Unmarshal(req.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		return &SendTxReply{TxHash: standInTxHash(params.Transaction)}, nil
+	}
+	for _, method := range []jsonrpc.RPCRequestType{jsonrpc.RPCTx, jsonrpc.RPCPrivateTx, RPCBSCPrivateTx, RPCPolygonPrivateTx} {
+		s.Handle(method, sendTx)
+	}
+
+	s.Handle(jsonrpc.RPCBatchTx, func(_ context.Context, req *bxtest.Request) (any, error) {
+		params := &SendTxBatchParams{}
+		if grpcReq, ok := req.GRPC.(*pb.BlxrBatchTXRequest); ok {
+			for _, tx := range grpcReq.TransactionsAndSenders {
+				params.Transactions = append(params.Transactions, tx.Transaction)
+			}
+		} else if err := json.Unmarshal(req.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		reply := &SendTxBatchReply{}
+		for i, tx := range params.Transactions {
+			reply.TxHashes = append(reply.TxHashes, BatchTxHash{Index: i, TxHash: standInTxHash(tx)})
+		}
+
+		return reply, nil
+	})
+
+	s.Handle(jsonrpc.RPCBundleSubmission, func(_ context.Context, req *bxtest.Request) (any, error) {
+		return &SendBundleReply{BundleHash: crypto.Keccak256Hash(req.Params).Hex()}, nil
+	})
+
+	s.Handle(RPCBSCGetBundlePrice, func(context.Context, *bxtest.Request) (any, error) {
+		return map[string]string{"bundlePrice": "0x3b9aca00"}, nil
+	})
+
+	s.Handle(jsonrpc.RPCQuotaUsage, func(_ context.Context, req *bxtest.Request) (any, error) {
+		if string(req.Params) != "{}" {
+			return nil, invalidParams(fmt.Errorf("expected empty params, got %s", req.Params))
+		}
+
+		return &QuotaUsage{AccountID: "stand-in", QuotaFilled: 10, QuotaLimit: 1000}, nil
+	})
+
+	// the monitored transactions are pending as soon as they are monitored
+	s.Handle(jsonrpc.RPCStartMonitoringTx, func(_ context.Context, req *bxtest.Request) (any, error) {
+		params := &monitorTxsParams{}
+		if err := json.Unmarshal(req.Params, params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		for _, tx := range params.Transactions {
+			status := map[string]string{"tx_hash": standInTxHash(tx), "status": "PENDING"}
+			if err := s.NotifySubscription(params.SubscriptionID, status); err != nil {
+				return nil, invalidParams(err)
+			}
+		}
+
+		return true, nil
+	})
+
+	s.Handle(jsonrpc.RPCStopMonitoringTx, func(context.Context, *bxtest.Request) (any, error) {
+		return true, nil
+	})
+}
+
+// streamStandInFeeds sends the notifications of every feed to the subscriptions of the stand-in until the test ends
+func streamStandInFeeds(t *testing.T, s *bxtest.Server) {
+	blocks := make(map[types.FeedType]json.RawMessage)
+	grpcBlocks := make(map[types.FeedType]*pb.BlocksReply)
+	for _, feed := range []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed} {
+		blocks[feed] = standInNotification(t, feed)
+		grpcBlocks[feed] = standInBlocksReply(t, blocks[feed])
+	}
+
+	receipt := standInNotification(t, types.TxReceiptsFeed)
+	grpcReceipt := standInTxReceiptsReply(t, receipt)
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		ticker := time.NewTicker(standInInterval)
+		defer ticker.Stop()
+
+		// the next transaction is sent once the previous one was sent to a subscription
+		var nonce uint64
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			tx, grpcTx := standInTxNotifications(standInTx(nonce))
+			var sent int
+			for _, feed := range []types.FeedType{types.NewTxsFeed, types.PendingTxsFeed} {
+				sent += s.Notify(feed, tx) + s.NotifyGRPC(feed, grpcTx)
+			}
+			if sent > 0 {
+				nonce++
+			}
+
+			for feed, block := range blocks {
+				s.Notify(feed, block)
+				s.NotifyGRPC(feed, grpcBlocks[feed])
+			}
+
+			s.Notify(types.TxReceiptsFeed, receipt)
+			s.NotifyGRPC(types.TxReceiptsFeed, grpcReceipt)
+
+			for _, sub := range s.Subscriptions(types.OnBlockFeed) {
+				notifyStandInCalls(s, sub)
+			}
+		}
+	}()
+}
+
+// standInTxNotifications returns the WS and gRPC notifications of a transaction
+func standInTxNotifications(tx *ethtypes.Transaction) (*NewTxNotification, *pb.TxsReply) {
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		panic(err)
+	}
+
+	now := time.Now()
+	notification := &NewTxNotification{
+		TxHash: tx.Hash().Hex(),
+		TxContents: &NewTxNotificationTxContents{
+			ChainId:  hexutil.EncodeBig(tx.ChainId()),
+			From:     from.Hex(),
+			Gas:      hexutil.EncodeUint64(tx.Gas()),
+			GasPrice: hexutil.EncodeBig(tx.GasPrice()),
+			Hash:     tx.Hash().Hex(),
+			Input:    hexutil.Encode(tx.Data()),
+			Nonce:    hexutil.EncodeUint64(tx.Nonce()),
+			To:       tx.To().Hex(),
+			Type:     hexutil.EncodeUint64(uint64(tx.Type())),
+			Value:    hexutil.EncodeBig(tx.Value()),
+		},
+		LocalRegion: true,
+		Time:        now.Format("2006-01-02 15:04:05.000000"),
+		RawTx:       hexutil.Encode(rawTx),
+	}
+
+	reply := &pb.TxsReply{Tx: []*pb.Tx{{From: from.Bytes(), LocalRegion: true, Time: now.UnixNano(), RawTx: rawTx}}}
+
+	return notification, reply
+}
+
+// standInBlocksReply returns the gRPC reply of a block notification
+func standInBlocksReply(t *testing.T, result json.RawMessage) *pb.BlocksReply {
+	t.Helper()
+
+	block := &OnBdnBlockNotification{}
+	require.NoError(t, json.Unmarshal(result, block))
+
+	reply := &pb.BlocksReply{Hash: block.Hash}
+	if h := block.Header; h != nil {
+		reply.Header = &pb.BlockHeader{
+			ParentHash:       h.ParentHash,
+			Sha3Uncles:       h.Sha3Uncles,
+			Miner:            h.Miner,
+			StateRoot:        h.StateRoot,
+			TransactionsRoot: h.TransactionsRoot,
+			ReceiptsRoot:     h.ReceiptsRoot,
+			LogsBloom:        h.LogsBloom,
+			Difficulty:       h.Difficulty,
+			Number:           h.Number,
+			GasLimit:         h.GasLimit,
+			GasUsed:          h.GasUsed,
+			Timestamp:        h.Timestamp,
+			ExtraData:        h.ExtraData,
+			MixHash:          h.MixHash,
+			Nonce:            h.Nonce,
+			BlobGasUsed:      h.BlobGasUsed,
+			ExcessBlobGas:    h.ExcessBlobGas,
+		}
+		if h.BaseFeePerGas != nil {
+			reply.Header.BaseFeePerGas = strconv.Itoa(*h.BaseFeePerGas)
+		}
+		if h.WithdrawalsRoot != nil {
+			reply.Header.WithdrawalsRoot = h.WithdrawalsRoot.Hex()
+		}
+		if h.ParentBeaconRoot != nil {
+			reply.Header.ParentBeaconRoot = h.ParentBeaconRoot.Hex()
+		}
+	}
+
+	for _, fv := range block.FutureValidatorInfo {
+		reply.FutureValidatorInfo = append(reply.FutureValidatorInfo, &pb.FutureValidatorInfo{BlockHeight: fv.BlockHeight, WalletId: fv.WalletId, Accessible: fv.Accessible})
+	}
+	for _, tx := range block.Transactions {
+		reply.Transaction = append(reply.Transaction, &pb.Tx{From: common.FromHex(tx.From), RawTx: tx.RawTx})
+	}
+	for _, w := range block.Withdrawals {
+		reply.Withdrawals = append(reply.Withdrawals, &pb.Withdrawal{Address: w.Address, Amount: w.Amount, Index: w.Index, ValidatorIndex: w.ValidatorIndex})
+	}
+
+	return reply
+}
+
+// standInTxReceiptsReply returns the gRPC reply of a transaction receipt notification
+func standInTxReceiptsReply(t *testing.T, result json.RawMessage) *pb.TxReceiptsReply {
+	t.Helper()
+
+	receipt := &OnTxReceiptNotification{}
+	require.NoError(t, json.Unmarshal(result, receipt))
+
+	contractAddress, _ := receipt.ContractAddress.(string)
+	reply := &pb.TxReceiptsReply{
+		BlocKHash:         receipt.BlockHash,
+		BlockNumber:       receipt.BlockNumber,
+		ContractAddress:   contractAddress,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		EffectiveGasUsed:  receipt.EffectiveGasUsed,
+		From:              receipt.From,
+		GasUsed:           receipt.GasUsed,
+		LogsBloom:         receipt.LogsBloom,
+		Status:            receipt.Status,
+		To:                receipt.To,
+		TransactionHash:   receipt.TransactionHash,
+		TransactionIndex:  receipt.TransactionIndex,
+		Type:              receipt.Type,
+		TxsCount:          receipt.TxsCount,
+		BlobGasUsed:       receipt.BlobGasUsed,
+		BlobGasPrice:      receipt.BlobGasPrice,
+	}
+	for _, log := range receipt.Logs {
+		reply.Logs = append(reply.Logs, &pb.TxLogs{
+			Address:          log.Address,
+			Topics:           log.Topics,
+			Data:             log.Data,
+			BlockNumber:      log.BlockNumber,
+			TransactionHash:  log.TransactionHash,
+			TransactionIndex: log.TransactionIndex,
+			BlockHash:        log.BlockHash,
+			LogIndex:         log.LogIndex,
+			Removed:          log.Removed,
+		})
+	}
+
+	return reply
+}
+
+// notifyStandInCalls sends the responses of the calls of an eth_onBlock subscription
+func notifyStandInCalls(s *bxtest.Server, sub bxtest.Subscription) {
+	var params struct {
+		CallParams []struct {
+			Name   string `json:"name"`
+			Method string `json:"method"`
+			Tag    string `json:"tag"`
+		} `json:"call-params"`
+	}
+	if err := json.Unmarshal(sub.Params, &params); err != nil {
+		return
+	}
+
+	for _, call := range params.CallParams {
+		_ = s.NotifySubscription(sub.ID, &OnBlockNotification{
+			Name:        call.Name,
+			Response:    standInCallResponses[call.Method],
+			BlockHeight: standInCallResponses["eth_blockNumber"],
+			Tag:         call.Tag,
+		})
+	}
+}
+
+// The tests below need to act on the endpoint, so they only run against the stand-ins
+
+func TestConformanceUnsubscribe(t *testing.T) {
+	for _, url := range []testURL{wsCloudApiUrl, wsGatewayUrl, grpcCloudApiUrl, grpcGatewayUrl} {
+		t.Run(strings.ToLower(string(url)), func(t *testing.T) {
+			config, s := testStandIn(t, url)
+
+			c, err := NewClient(context.Background(), config)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, c.Close()) }()
+
+			sub, err := c.SubscribeNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			require.NoError(t, s.WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+			serverID := s.Subscriptions(types.NewTxsFeed)[0].ID
+
+			require.NoError(t, sub.Unsubscribe())
+			require.Eventually(t, func() bool { return len(s.Subscriptions(types.NewTxsFeed)) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+			if url == grpcCloudApiUrl || url == grpcGatewayUrl {
+				// a gRPC subscription ends with its stream
+				return
+			}
+
+			var unsubscribed bool
+			for _, req := range s.Requests() {
+				if req.Method == jsonrpc.RPCUnsubscribe {
+					require.JSONEq(t, fmt.Sprintf("[%q]", serverID), string(req.Params))
+					unsubscribed = true
+				}
+			}
+			require.True(t, unsubscribed, "no unsubscribe request")
+		})
+	}
+}
+
+func TestConformanceResubscribe(t *testing.T) {
+	for _, url := range []testURL{wsCloudApiUrl, wsGatewayUrl, grpcCloudApiUrl, grpcGatewayUrl} {
+		t.Run(strings.ToLower(string(url)), func(t *testing.T) {
+			config, s := testStandIn(t, url)
+
+			resubscribed := make(chan struct{}, 1)
+			config.OnEvent = func(event Event) {
+				if event.Type == EventResubscribed {
+					select {
+					case resubscribed <- struct{}{}:
+					default:
+					}
+				}
+			}
+
+			c, err := NewClient(context.Background(), config)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, c.Close()) }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			txs, err := c.OnNewTxChan(ctx, nil)
+			require.NoError(t, err)
+
+			receiveStandInTx := func() {
+				select {
+				case n := <-txs:
+					require.NoError(t, n.Err)
+					require.NotEmpty(t, n.Result.RawTx)
+				case <-ctx.Done():
+					t.Fatal("timeout waiting for new tx")
+				}
+			}
+			receiveStandInTx()
+
+			// the stand-in drops the connection, or the stream over gRPC, and the subscription with it
+			s.Disconnect()
+
+			select {
+			case <-resubscribed:
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for the resubscription")
+			}
+			require.NoError(t, s.WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+
+			for len(txs) > 0 {
+				<-txs
+			}
+			receiveStandInTx()
+		})
+	}
+}
+
+func TestConformanceRequestTimeout(t *testing.T) {
+	for _, url := range []testURL{wsCloudApiUrl, wsGatewayUrl, grpcCloudApiUrl, grpcGatewayUrl} {
+		t.Run(strings.ToLower(string(url)), func(t *testing.T) {
+			config, s := testStandIn(t, url)
+
+			c, err := NewClient(context.Background(), config)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, c.Close()) }()
+
+			params := &SendTxParams{Transaction: testTxBytes(t)}
+
+			s.Delay(jsonrpc.RPCTx, time.Second)
+			_, err = c.SendTx(context.Background(), params, WithTimeout(100*time.Millisecond))
+			require.Error(t, err)
+			require.Truef(t, errors.Is(err, ErrRequestTimeout) || errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded,
+				"unexpected error: %v", err)
+
+			// the connection is still usable, and the late response to the request is ignored
+			s.Delay(jsonrpc.RPCTx, 0)
+			res, err := c.SendTx(context.Background(), params)
+			require.NoError(t, err)
+
+			reply := &SendTxReply{}
+			require.NoError(t, json.Unmarshal(*res, reply))
+			require.Equal(t, standInTxHash(params.Transaction), reply.TxHash)
+		})
+	}
+}
+
+func TestConformanceMonitorTxs(t *testing.T) {
+	config, s := testStandIn(t, wsCloudApiUrl)
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses, err := c.OnTxStatusChan(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.WaitSubscriptions(ctx, types.TransactionStatusFeed, 1))
+	serverID := s.Subscriptions(types.TransactionStatusFeed)[0].ID
+
+	tx := testTxBytes(t)
+	require.NoError(t, c.MonitorTxs(ctx, &MonitorTxsParams{Transactions: []string{tx}}))
+
+	select {
+	case n := <-statuses:
+		require.NoError(t, n.Err)
+		require.Equal(t, standInTxHash(tx), n.Result.TxHash)
+		require.Equal(t, "PENDING", n.Result.Status)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the transaction status")
+	}
+
+	require.NoError(t, c.StopMonitoringTx(ctx, &StopMonitoringTxParams{
+		Transactions:    []string{tx},
+		TransactionHash: []string{standInTxHash(tx)},
+	}))
+	require.Eventually(t, func() bool { return len(s.Subscriptions(types.TransactionStatusFeed)) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// both requests name the transactions and the subscription of the feed by its server ID
+	var started, stopped bool
+	for _, req := range s.Requests() {
+		switch req.Method {
+		case jsonrpc.RPCStartMonitoringTx:
+			require.JSONEq(t, fmt.Sprintf(`{"transactions":[%q],"subscription_id":%q}`, tx, serverID), string(req.Params))
+			started = true
+		case jsonrpc.RPCStopMonitoringTx:
+			require.JSONEq(t, fmt.Sprintf(`{"transactions":[%q],"transaction_hash":[%q],"subscription_id":%q}`,
+				tx, standInTxHash(tx), serverID), string(req.Params))
+			stopped = true
+		}
+	}
+	require.True(t, started, "no start monitoring request")
+	require.True(t, stopped, "no stop monitoring request")
+}
+
+func TestConformanceMonitorTxsSubscriptions(t *testing.T) {
+	config, s := testStandIn(t, wsCloudApiUrl)
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subscribe := func() (*Subscription, chan *OnTxStatusNotification) {
+		statuses := make(chan *OnTxStatusNotification, 10)
+		sub, err := c.SubscribeTxStatus(ctx, OnTxStatusParams{Callback: func(_ context.Context, err error, n *OnTxStatusNotification) {
+			require.NoError(t, err)
+			statuses <- n
+		}})
+		require.NoError(t, err)
+
+		return sub, statuses
+	}
+	first, firstStatuses := subscribe()
+	second, secondStatuses := subscribe()
+	require.NoError(t, s.WaitSubscriptions(ctx, types.TransactionStatusFeed, 2))
+
+	tx := testTxBytes(t)
+
+	// with several subscriptions, the one to monitor the transactions on must be given
+	require.ErrorIs(t, c.MonitorTxs(ctx, &MonitorTxsParams{Transactions: []string{tx}}), ErrSeveralSubIDs)
+
+	require.NoError(t, c.MonitorTxs(ctx, &MonitorTxsParams{Transactions: []string{tx}, Subscription: second}))
+	select {
+	case n := <-secondStatuses:
+		require.Equal(t, standInTxHash(tx), n.TxHash)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the transaction status")
+	}
+	require.Empty(t, firstStatuses)
+
+	// stopping ends only the given subscription
+	require.NoError(t, c.StopMonitoringTx(ctx, &StopMonitoringTxParams{
+		Transactions:    []string{tx},
+		TransactionHash: []string{standInTxHash(tx)},
+		Subscription:    second,
+	}))
+	require.Eventually(t, func() bool { return len(s.Subscriptions(types.TransactionStatusFeed)) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// the remaining subscription is the only one, so it doesn't need to be given
+	require.NoError(t, c.MonitorTxs(ctx, &MonitorTxsParams{Transactions: []string{tx}}))
+	select {
+	case n := <-firstStatuses:
+		require.Equal(t, standInTxHash(tx), n.TxHash)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the transaction status")
+	}
+
+	require.NoError(t, first.Unsubscribe())
+}
+
+func TestConformanceBundleSubmission(t *testing.T) {
+	for _, url := range []testURL{wsCloudApiUrl, wsGatewayUrl, grpcGatewayUrl} {
+		t.Run(strings.ToLower(string(url)), func(t *testing.T) {
+			config, s := testStandIn(t, url)
+
+			c, err := NewClient(context.Background(), config)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, c.Close()) }()
+
+			tx := testTxBytes(t)
+			submit := map[string]func() (*json.RawMessage, error){
+				"eth": func() (*json.RawMessage, error) {
+					return c.SendEthBundle(context.Background(), &SendEthBundleParams{Transactions: []string{tx}, BlockNumber: "0x1"})
+				},
+				"bsc": func() (*json.RawMessage, error) {
+					return c.SendBscBundle(context.Background(), &SendBscBundleParams{Transactions: []string{tx}, BlockNumber: "0x1"})
+				},
+			}
+
+			for network, send := range submit {
+				res, err := send()
+				require.NoError(t, err, network)
+
+				requests := s.Requests()
+				req := requests[len(requests)-1]
+				require.Equal(t, jsonrpc.RPCBundleSubmission, req.Method, network)
+
+				// the stand-in answers with the hash of the params it received
+				reply := &SendBundleReply{}
+				require.NoError(t, json.Unmarshal(*res, reply), network)
+				require.Equal(t, crypto.Keccak256Hash(req.Params).Hex(), reply.BundleHash, network)
+
+				if grpcReq, ok := req.GRPC.(*pb.BlxrSubmitBundleRequest); ok {
+					require.Equal(t, []string{tx}, grpcReq.Transactions, network)
+					require.Equal(t, "0x1", grpcReq.BlockNumber, network)
+					continue
+				}
+
+				params := &struct {
+					Transactions []string `json:"transaction"`
+					BlockNumber  string   `json:"block_number"`
+				}{}
+				require.NoError(t, json.Unmarshal(req.Params, params), network)
+				require.Equal(t, []string{tx}, params.Transactions, network)
+				require.Equal(t, "0x1", params.BlockNumber, network)
+			}
+		})
+	}
+}
+
+func TestDecodingParity(t *testing.T) {
+	wsConfig, _ := testStandIn(t, wsGatewayUrl)
+	grpcConfig, _ := testStandIn(t, grpcGatewayUrl)
+
+	ws, err := NewClient(context.Background(), wsConfig)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ws.Close()) }()
+
+	grpc, err := NewClient(context.Background(), grpcConfig)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, grpc.Close()) }()
+
+	t.Run("new_txs", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		wsTx := firstNotification(t, ctx, func() (<-chan Notification[*NewTxNotification], error) { return ws.OnNewTxChan(ctx, nil) })
+		grpcTx := firstNotification(t, ctx, func() (<-chan Notification[*NewTxNotification], error) { return grpc.OnNewTxChan(ctx, nil) })
+
+		// WS notifications carry a hex-encoded transaction and gRPC notifications a binary one
+		require.Equal(t, notificationKey(wsTx), notificationKey(grpcTx))
+		require.Equal(t, wsTx.TxHash, notificationKey(grpcTx))
+		require.Equal(t, wsTx.LocalRegion, grpcTx.LocalRegion)
+	})
+
+	requireBlocksEqual := func(t *testing.T, wsBlock, grpcBlock *OnBdnBlockNotification) {
+		require.Equal(t, wsBlock.Hash, grpcBlock.Hash)
+		require.Equal(t, wsBlock.Header, grpcBlock.Header)
+		require.Equal(t, wsBlock.FutureValidatorInfo, grpcBlock.FutureValidatorInfo)
+		require.Equal(t, wsBlock.Withdrawals, grpcBlock.Withdrawals)
+		// the transactions of a block differ in WS and gRPC
+		require.Len(t, grpcBlock.Transactions, len(wsBlock.Transactions))
+	}
+
+	t.Run("new_blocks", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		params := &NewBlockParams{Include: []string{"hash", "header", "transactions"}}
+		wsBlock := firstNotification(t, ctx, func() (<-chan Notification[*OnBdnBlockNotification], error) { return ws.OnNewBlockChan(ctx, params) })
+		grpcBlock := firstNotification(t, ctx, func() (<-chan Notification[*OnBdnBlockNotification], error) { return grpc.OnNewBlockChan(ctx, params) })
+
+		requireBlocksEqual(t, wsBlock, grpcBlock)
+	})
+
+	t.Run("bdn_blocks", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		params := &BdnBlockParams{Include: []string{"hash", "header", "transactions"}}
+		wsBlock := firstNotification(t, ctx, func() (<-chan Notification[*OnBdnBlockNotification], error) { return ws.OnBdnBlockChan(ctx, params) })
+		grpcBlock := firstNotification(t, ctx, func() (<-chan Notification[*OnBdnBlockNotification], error) { return grpc.OnBdnBlockChan(ctx, params) })
+
+		requireBlocksEqual(t, wsBlock, grpcBlock)
+	})
+
+	t.Run("tx_receipts", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		wsReceipt := firstNotification(t, ctx, func() (<-chan Notification[*OnTxReceiptNotification], error) { return ws.OnTxReceiptChan(ctx, nil) })
+		grpcReceipt := firstNotification(t, ctx, func() (<-chan Notification[*OnTxReceiptNotification], error) { return grpc.OnTxReceiptChan(ctx, nil) })
+
+		// WS has no contract address as null and gRPC as an empty string
+		require.Nil(t, wsReceipt.ContractAddress)
+		require.Equal(t, "", grpcReceipt.ContractAddress)
+		grpcReceipt.ContractAddress = nil
+
+		require.Equal(t, wsReceipt, grpcReceipt)
+	})
+}
+
+// firstNotification subscribes and returns the first notification
+func firstNotification[T any](t *testing.T, ctx context.Context, subscribe func() (<-chan Notification[T], error)) T {
+	t.Helper()
+
+	ch, err := subscribe()
+	require.NoError(t, err)
+
+	select {
+	case n := <-ch:
+		require.NoError(t, n.Err)
+		return n.Result
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the first notification")
+		var zero T
+		return zero
+	}
+}
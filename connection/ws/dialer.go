@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/fasthttp/websocket"
 )
@@ -19,8 +23,16 @@ const (
 
 type connection struct {
 	remoteAddress string
+	opts          *DialOptions
+	logger        *slog.Logger
 	closed        chan struct{}
 	msgsJSON      chan interface{}
+	// priorityMsgsJSON is drained before msgsJSON
+	priorityMsgsJSON chan interface{}
+	// lastMessage is the time of the last message received in unix nanoseconds
+	lastMessage atomic.Int64
+	// stale is set when the connection is closed by the heartbeat
+	stale atomic.Bool
 
 	fastHTTPConn *websocket.Conn
 }
@@ -28,9 +40,10 @@ type connection struct {
 // Dial dials websocket connection which is safe to use in goroutines.
 func Dial(ctx context.Context, url string, headers http.Header, opts *DialOptions) (Conn, error) {
 	c := &connection{
-		remoteAddress: url,
-		closed:        make(chan struct{}),
-		msgsJSON:      make(chan interface{}, msgCHanSize),
+		remoteAddress:    url,
+		closed:           make(chan struct{}),
+		msgsJSON:         make(chan interface{}, msgCHanSize),
+		priorityMsgsJSON: make(chan interface{}, msgCHanSize),
 	}
 
 	if opts == nil {
@@ -41,15 +54,36 @@ func Dial(ctx context.Context, url string, headers http.Header, opts *DialOption
 		opts.HandshakeTimeout = DefaultHandshakeTimeout
 	}
 
+	if opts.PingInterval == 0 {
+		opts.PingInterval = DefaultPingInterval
+	}
+
+	if opts.PongTimeout == 0 {
+		opts.PongTimeout = DefaultPongTimeout
+	}
+
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = DefaultWriteTimeout
+	}
+
+	c.opts = opts
+
+	c.logger = opts.Logger
+	if c.logger == nil {
+		c.logger = slog.New(discardHandler{})
+	}
+
 	if opts.TLSClientConfig == nil {
 		opts.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
+			MinVersion: tls.VersionTLS12,
 		}
 	}
 
 	dialer := websocket.Dialer{
 		HandshakeTimeout: opts.HandshakeTimeout,
 		TLSClientConfig:  opts.TLSClientConfig,
+		Proxy:            opts.Proxy,
+		NetDialContext:   opts.NetDialContext,
 	}
 
 	var err error
@@ -64,10 +98,27 @@ func Dial(ctx context.Context, url string, headers http.Header, opts *DialOption
 		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
 	}
 
+	c.logger.Debug("websocket connection established", slog.String("remote_address", c.remoteAddress))
+
 	c.fastHTTPConn.SetReadLimit(messageSizeLimit)
+	c.lastMessage.Store(time.Now().UnixNano())
+
+	if opts.PingInterval > 0 {
+		// the read deadline is extended by every message and pong,
+		// so reads fail if the server doesn't answer pings
+		c.extendReadDeadline()
+		c.fastHTTPConn.SetPongHandler(func(string) error {
+			c.extendReadDeadline()
+			return nil
+		})
+	}
 
 	go c.write()
 
+	if opts.PingInterval > 0 || opts.IdleTimeout > 0 {
+		go c.heartbeat()
+	}
+
 	return c, nil
 }
 
@@ -82,24 +133,50 @@ func (c *connection) ReadMessage(ctx context.Context) (data []byte, err error) {
 	}
 
 	_, data, err = c.fastHTTPConn.ReadMessage()
+	if err != nil {
+		if c.stale.Load() || isTimeoutError(err) {
+			if !c.stale.Swap(true) {
+				c.logger.Warn("closing websocket connection which stopped answering pings", slog.Any("error", err))
+			}
+			_ = c.Close()
+			return nil, fmt.Errorf("%w: %s", ErrStale, err)
+		}
+
+		if IsWSClosedError(err) {
+			return nil, c.Close()
+		}
+
+		return nil, err
+	}
 
-	if err != nil && IsWSClosedError(err) {
-		return nil, c.Close()
+	c.lastMessage.Store(time.Now().UnixNano())
+	if c.opts.PingInterval > 0 {
+		c.extendReadDeadline()
 	}
 
-	return data, err
+	return data, nil
 }
 
 // WriteJSON writes JSON message to websocket connection.
 func (c *connection) WriteJSON(ctx context.Context, v interface{}) error {
+	return c.enqueue(ctx, c.msgsJSON, v)
+}
+
+// WritePriorityJSON writes JSON message to websocket connection ahead of the messages written with WriteJSON.
+func (c *connection) WritePriorityJSON(ctx context.Context, v interface{}) error {
+	return c.enqueue(ctx, c.priorityMsgsJSON, v)
+}
+
+func (c *connection) enqueue(ctx context.Context, msgs chan interface{}, v interface{}) error {
 	select {
 	case <-c.closed:
 		return ErrAlreadyClosed
 	case <-ctx.Done():
 		return nil
-	case c.msgsJSON <- v:
+	case msgs <- v:
 	default:
 		// in case the channel is full, we close the connection
+		c.logger.Warn("closing websocket connection with a full write queue", slog.Int("queued", len(msgs)))
 		return c.Close()
 	}
 
@@ -108,15 +185,33 @@ func (c *connection) WriteJSON(ctx context.Context, v interface{}) error {
 
 func (c *connection) write() {
 	for {
+		var msg interface{}
+
 		select {
-		case <-c.closed:
-			return
-		case msg := <-c.msgsJSON:
-			err := c.writeTimeoutJSON(msg)
-			if err != nil && IsWSClosedError(err) {
+		case msg = <-c.priorityMsgsJSON:
+		default:
+			select {
+			case <-c.closed:
 				return
+			case msg = <-c.priorityMsgsJSON:
+			case msg = <-c.msgsJSON:
 			}
 		}
+
+		err := c.writeTimeoutJSON(msg)
+		if isTimeoutError(err) {
+			// a timed out write leaves the connection in an unusable state
+			c.logger.Warn("closing websocket connection after a write timeout", slog.Any("error", err))
+			c.stale.Store(true)
+			_ = c.Close()
+			return
+		}
+		if err != nil && IsWSClosedError(err) {
+			return
+		}
+		if err != nil {
+			c.logger.Error("failed to write message", slog.Any("error", err))
+		}
 	}
 }
 
@@ -151,6 +246,7 @@ func IsWSClosedError(err error) bool {
 	var websocketCloseErr *websocket.CloseError
 	if errors.As(err, &websocketCloseErr) ||
 		errors.Is(err, ErrAlreadyClosed) ||
+		errors.Is(err, ErrStale) ||
 		errors.Is(err, io.EOF) ||
 		strings.Contains(err.Error(), "already wrote close") ||
 		strings.Contains(err.Error(), "EOF") ||
@@ -165,5 +261,60 @@ func IsWSClosedError(err error) bool {
 // writeTimeoutJSON writes JSON message to websocket connection with timeout.
 // It should not be used for concurrent writes.
 func (c *connection) writeTimeoutJSON(msg interface{}) error {
+	err := c.fastHTTPConn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout))
+	if err != nil {
+		return err
+	}
+
 	return c.fastHTTPConn.WriteJSON(msg)
 }
+
+// heartbeat pings the server and closes the connection as stale when
+// no message is received within the idle timeout.
+func (c *connection) heartbeat() {
+	// a nil channel never fires, which disables the corresponding check
+	var ping, idle <-chan time.Time
+
+	if c.opts.PingInterval > 0 {
+		pingTicker := time.NewTicker(c.opts.PingInterval)
+		defer pingTicker.Stop()
+		ping = pingTicker.C
+	}
+
+	if c.opts.IdleTimeout > 0 {
+		idleTicker := time.NewTicker(c.opts.IdleTimeout / 4)
+		defer idleTicker.Stop()
+		idle = idleTicker.C
+	}
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case now := <-idle:
+			if now.Sub(time.Unix(0, c.lastMessage.Load())) > c.opts.IdleTimeout {
+				c.logger.Warn("closing idle websocket connection", slog.Duration("idle_timeout", c.opts.IdleTimeout))
+				c.stale.Store(true)
+				_ = c.Close()
+				return
+			}
+		case now := <-ping:
+			// WriteControl is safe to use concurrently with the other write methods
+			err := c.fastHTTPConn.WriteControl(websocket.PingMessage, nil, now.Add(c.opts.WriteTimeout))
+			if err != nil && IsWSClosedError(err) {
+				return
+			}
+		}
+	}
+}
+
+// extendReadDeadline gives the server another ping interval and pong timeout to send a message.
+func (c *connection) extendReadDeadline() {
+	_ = c.fastHTTPConn.SetReadDeadline(time.Now().Add(c.opts.PingInterval + c.opts.PongTimeout))
+}
+
+// isTimeoutError checks if error is a network timeout error.
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
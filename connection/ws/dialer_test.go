@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer starts a websocket server which keeps reading from the connection,
+// answers pings if requested and sends the given messages
+func testServer(t *testing.T, answerPings bool, messages ...string) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if !answerPings {
+			conn.SetPingHandler(func(string) error { return nil })
+		}
+
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestDialHeartbeat(t *testing.T) {
+	t.Run("pong_timeout", func(t *testing.T) {
+		url := testServer(t, false, "hello")
+
+		conn, err := Dial(context.Background(), url, nil, &DialOptions{
+			PingInterval: 50 * time.Millisecond,
+			PongTimeout:  50 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		message, err := conn.ReadMessage(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(message))
+
+		start := time.Now()
+		_, err = conn.ReadMessage(context.Background())
+		require.ErrorIs(t, err, ErrStale)
+		require.True(t, IsWSClosedError(err))
+		require.Less(t, time.Since(start), time.Second)
+
+		_, err = conn.ReadMessage(context.Background())
+		require.ErrorIs(t, err, ErrAlreadyClosed)
+	})
+
+	t.Run("idle_timeout", func(t *testing.T) {
+		url := testServer(t, true)
+
+		conn, err := Dial(context.Background(), url, nil, &DialOptions{
+			PingInterval: 20 * time.Millisecond,
+			IdleTimeout:  200 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = conn.ReadMessage(context.Background())
+		require.ErrorIs(t, err, ErrStale)
+		require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		url := testServer(t, true)
+
+		conn, err := Dial(context.Background(), url, nil, &DialOptions{
+			PingInterval: 20 * time.Millisecond,
+			PongTimeout:  20 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		read := make(chan error, 1)
+		go func() {
+			_, err := conn.ReadMessage(context.Background())
+			read <- err
+		}()
+
+		// pongs keep the connection alive while the server is quiet
+		select {
+		case err := <-read:
+			require.Failf(t, "unexpected read result", "%v", err)
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		require.NoError(t, conn.Close())
+		<-read
+	})
+}
@@ -0,0 +1,180 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// FrameType is the type of a frame of a recording
+type FrameType string
+
+// FrameType enumeration
+const (
+	// FrameOpen starts a connection, and carries the URL it was dialed with
+	FrameOpen FrameType = "open"
+	// FrameRead is a message read from the server
+	FrameRead FrameType = "read"
+	// FrameWrite is a message written to the server
+	FrameWrite FrameType = "write"
+	// FrameClose ends a connection, and carries the error which ended it, if any
+	FrameClose FrameType = "close"
+)
+
+// Frame is a single line of a recording, which is a stream of JSON frames
+type Frame struct {
+	Time time.Time `json:"time"`
+	// Conn is the sequence number of the connection in the recording, starting from 1
+	Conn int       `json:"conn"`
+	Type FrameType `json:"type"`
+	URL  string    `json:"url,omitempty"`
+	// Data is the message, if it is JSON
+	Data json.RawMessage `json:"data,omitempty"`
+	// Raw is the message, if it isn't JSON
+	Raw string `json:"raw,omitempty"`
+	Err string `json:"err,omitempty"`
+}
+
+// message returns the message of a read or write frame
+func (f *Frame) message() []byte {
+	if len(f.Data) != 0 {
+		return f.Data
+	}
+
+	return []byte(f.Raw)
+}
+
+// Recorder writes every message read from and written to the connections it wraps to a recording,
+// with timestamps and connection boundaries, so that it can be replayed with NewReplay.
+// It is safe to use from several goroutines.
+type Recorder struct {
+	lock    *sync.Mutex
+	encoder *json.Encoder
+	conns   int
+	err     error
+}
+
+// NewRecorder returns a Recorder which writes the recording to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		lock:    &sync.Mutex{},
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Wrap returns a connection which records the messages of conn as a new connection to the URL.
+// It can be used as the WrapWSConn of the SDK config, so that the connections are still made by its WSConnectFunc.
+func (r *Recorder) Wrap(url string, conn Conn) Conn {
+	r.lock.Lock()
+	r.conns++
+	id := r.conns
+	r.lock.Unlock()
+
+	r.record(&Frame{Conn: id, Type: FrameOpen, URL: url})
+
+	return &recordingConn{Conn: conn, recorder: r, id: id}
+}
+
+// Err returns the first error writing the recording, after which nothing more is recorded
+func (r *Recorder) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.err
+}
+
+func (r *Recorder) record(frame *Frame) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	frame.Time = time.Now()
+	if err := r.encoder.Encode(frame); err != nil {
+		r.err = fmt.Errorf("failed to record frame: %w", err)
+	}
+}
+
+// recordMessage records a message as JSON data if it is JSON, or as a raw string otherwise
+func (r *Recorder) recordMessage(id int, typ FrameType, message []byte) {
+	frame := &Frame{Conn: id, Type: typ}
+	if json.Valid(message) {
+		frame.Data = message
+	} else {
+		frame.Raw = string(message)
+	}
+
+	r.record(frame)
+}
+
+type recordingConn struct {
+	Conn
+	recorder  *Recorder
+	id        int
+	closeOnce sync.Once
+}
+
+func (c *recordingConn) ReadMessage(ctx context.Context) ([]byte, error) {
+	message, err := c.Conn.ReadMessage(ctx)
+	if err != nil {
+		if IsWSClosedError(err) {
+			c.recordClose(err)
+		}
+
+		return message, err
+	}
+
+	c.recorder.recordMessage(c.id, FrameRead, message)
+
+	return message, nil
+}
+
+func (c *recordingConn) WriteJSON(ctx context.Context, v interface{}) error {
+	c.recordWrite(v)
+
+	return c.Conn.WriteJSON(ctx, v)
+}
+
+// WritePriorityJSON writes ahead of the other messages if the wrapped connection supports it
+func (c *recordingConn) WritePriorityJSON(ctx context.Context, v interface{}) error {
+	c.recordWrite(v)
+
+	if pw, ok := c.Conn.(PriorityWriter); ok {
+		return pw.WritePriorityJSON(ctx, v)
+	}
+
+	return c.Conn.WriteJSON(ctx, v)
+}
+
+func (c *recordingConn) Close() error {
+	c.recordClose(nil)
+
+	return c.Conn.Close()
+}
+
+func (c *recordingConn) recordWrite(v interface{}) {
+	message, err := json.Marshal(v)
+	if err != nil {
+		// the connection fails to write it as well
+		return
+	}
+
+	c.recorder.recordMessage(c.id, FrameWrite, message)
+}
+
+// recordClose records the end of the connection once, whether it was closed or lost
+func (c *recordingConn) recordClose(err error) {
+	c.closeOnce.Do(func() {
+		frame := &Frame{Conn: c.id, Type: FrameClose}
+		if err != nil {
+			frame.Err = err.Error()
+		}
+
+		c.recorder.record(frame)
+	})
+}
@@ -0,0 +1,168 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRecording returns a recording of a single connection with the given frames, 100ms apart
+func testRecording(t *testing.T, frames ...*Frame) *bytes.Buffer {
+	t.Helper()
+
+	recording := &bytes.Buffer{}
+	encoder := json.NewEncoder(recording)
+	start := time.Now()
+	for i, frame := range frames {
+		frame.Conn = 1
+		frame.Time = start.Add(time.Duration(i) * 100 * time.Millisecond)
+		require.NoError(t, encoder.Encode(frame))
+	}
+
+	return recording
+}
+
+func TestRecordReplay(t *testing.T) {
+	t.Run("record", func(t *testing.T) {
+		url := testServer(t, true, `{"jsonrpc":"2.0","method":"subscribe","params":{}}`, "not json")
+		recording := &bytes.Buffer{}
+		recorder := NewRecorder(recording)
+
+		dialed, err := Dial(context.Background(), url, nil, nil)
+		require.NoError(t, err)
+		conn := recorder.Wrap(url, dialed)
+
+		require.NoError(t, conn.WriteJSON(context.Background(), map[string]string{"id": "1", "method": "subscribe"}))
+		for range 2 {
+			_, err = conn.ReadMessage(context.Background())
+			require.NoError(t, err)
+		}
+		require.NoError(t, conn.Close())
+		require.NoError(t, recorder.Err())
+
+		var frames []Frame
+		for _, line := range strings.Split(strings.TrimSpace(recording.String()), "\n") {
+			var frame Frame
+			require.NoError(t, json.Unmarshal([]byte(line), &frame))
+			require.Equal(t, 1, frame.Conn)
+			require.False(t, frame.Time.IsZero())
+			frames = append(frames, frame)
+		}
+
+		require.Len(t, frames, 5)
+		require.Equal(t, FrameOpen, frames[0].Type)
+		require.Equal(t, url, frames[0].URL)
+		require.Equal(t, FrameWrite, frames[1].Type)
+		require.JSONEq(t, `{"id":"1","method":"subscribe"}`, string(frames[1].Data))
+		require.Equal(t, FrameRead, frames[2].Type)
+		require.JSONEq(t, `{"jsonrpc":"2.0","method":"subscribe","params":{}}`, string(frames[2].Data))
+		require.Equal(t, FrameRead, frames[3].Type)
+		require.Equal(t, "not json", frames[3].Raw)
+		require.Equal(t, FrameClose, frames[4].Type)
+	})
+
+	t.Run("replace_ids", func(t *testing.T) {
+		replay, err := NewReplay(testRecording(t,
+			&Frame{Type: FrameOpen},
+			&Frame{Type: FrameWrite, Data: json.RawMessage(`{"id":"1","method":"subscribe"}`)},
+			&Frame{Type: FrameRead, Data: json.RawMessage(`{"id":"1","result":"sub"}`)},
+			&Frame{Type: FrameRead, Raw: "not json"},
+		), nil)
+		require.NoError(t, err)
+
+		conn, err := replay.Dial(context.Background(), "", nil, nil)
+		require.NoError(t, err)
+
+		// the response waits for its request
+		read := make(chan []byte, 1)
+		go func() {
+			message, err := conn.ReadMessage(context.Background())
+			if err == nil {
+				read <- message
+			}
+		}()
+
+		select {
+		case <-read:
+			t.Fatal("response read before its request was written")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		require.NoError(t, conn.WriteJSON(context.Background(), map[string]string{"id": "42", "method": "subscribe"}))
+		select {
+		case message := <-read:
+			require.JSONEq(t, `{"id":"42","result":"sub"}`, string(message))
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the response")
+		}
+
+		message, err := conn.ReadMessage(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "not json", string(message))
+
+		// the last connection stays open until it is closed
+		go func() { _ = conn.Close() }()
+		_, err = conn.ReadMessage(context.Background())
+		require.ErrorIs(t, err, ErrAlreadyClosed)
+
+		select {
+		case <-replay.Done():
+		default:
+			t.Fatal("replay isn't done")
+		}
+
+		_, err = replay.Dial(context.Background(), "", nil, nil)
+		require.ErrorIs(t, err, ErrReplayFinished)
+	})
+
+	t.Run("speed", func(t *testing.T) {
+		frames := []*Frame{{Type: FrameOpen}}
+		for range 5 {
+			frames = append(frames, &Frame{Type: FrameRead, Data: json.RawMessage(`{}`)})
+		}
+
+		// 500ms of messages are read in about 50ms
+		replay, err := NewReplay(testRecording(t, frames...), &ReplayOptions{Speed: 10})
+		require.NoError(t, err)
+
+		conn, err := replay.Dial(context.Background(), "", nil, nil)
+		require.NoError(t, err)
+
+		start := time.Now()
+		for range 5 {
+			_, err = conn.ReadMessage(context.Background())
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond)
+		require.Less(t, time.Since(start), 400*time.Millisecond)
+	})
+
+	t.Run("step", func(t *testing.T) {
+		replay, err := NewReplay(testRecording(t,
+			&Frame{Type: FrameOpen},
+			&Frame{Type: FrameRead, Data: json.RawMessage(`1`)},
+			&Frame{Type: FrameRead, Data: json.RawMessage(`2`)},
+		), &ReplayOptions{Step: true})
+		require.NoError(t, err)
+
+		conn, err := replay.Dial(context.Background(), "", nil, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = conn.ReadMessage(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		for _, expected := range []string{"1", "2"} {
+			go replay.Step()
+			message, err := conn.ReadMessage(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, expected, string(message))
+		}
+	})
+}
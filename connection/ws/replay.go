@@ -0,0 +1,352 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrReplayFinished is returned by Replay.Dial when all the connections of the recording were replayed
+var ErrReplayFinished = errors.New("replay finished")
+
+// ReplayOptions decide how fast a recording is replayed
+type ReplayOptions struct {
+	// Speed is how many times faster than recorded the messages are read, e.g. 1 for the original speed.
+	// Default: 0, as fast as possible
+	Speed float64
+
+	// Step makes each message wait for a call of Replay.Step, e.g. to walk through a session in a debugger
+	Step bool
+}
+
+// Replay replays a recording of a Recorder. Each Dial returns the next connection of the recording,
+// whose reads return the recorded messages. A message which was read after the client wrote a request,
+// e.g. the response to a subscription, is only read after the client wrote as many messages again, and
+// the IDs of the responses are replaced with the IDs of the requests written during the replay.
+// After the messages of a connection are read, it fails as closed, like the recorded connection did,
+// except for the last connection which stays open until it is closed.
+type Replay struct {
+	conns [][]*Frame
+	opts  ReplayOptions
+	lock  *sync.Mutex
+	next  int
+	steps chan struct{}
+	done  chan struct{}
+}
+
+// NewReplay reads the recording from r
+func NewReplay(r io.Reader, opts *ReplayOptions) (*Replay, error) {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+
+	// the connections are replayed in the order they were opened
+	var conns [][]*Frame
+	index := make(map[int]int)
+
+	decoder := json.NewDecoder(r)
+	for {
+		frame := &Frame{}
+		err := decoder.Decode(frame)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+
+		i, ok := index[frame.Conn]
+		if !ok {
+			i = len(conns)
+			index[frame.Conn] = i
+			conns = append(conns, nil)
+		}
+
+		conns[i] = append(conns[i], frame)
+	}
+
+	return &Replay{
+		conns: conns,
+		opts:  *opts,
+		lock:  &sync.Mutex{},
+		steps: make(chan struct{}),
+		done:  make(chan struct{}),
+	}, nil
+}
+
+// Dial returns the next connection of the recording, whatever the URL. It can be used as the WSConnectFunc of the SDK config.
+func (r *Replay) Dial(context.Context, string, http.Header, *DialOptions) (Conn, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.next >= len(r.conns) {
+		return nil, ErrReplayFinished
+	}
+
+	conn := newReplayConn(r, r.conns[r.next], r.next == len(r.conns)-1)
+	r.next++
+
+	return conn, nil
+}
+
+// Step releases the next message when the replay is stepwise, and returns once it is read or the replay is done
+func (r *Replay) Step() {
+	select {
+	case r.steps <- struct{}{}:
+	case <-r.done:
+	}
+}
+
+// Done is closed when all the messages of the recording were read
+func (r *Replay) Done() <-chan struct{} {
+	return r.done
+}
+
+// replayRead is a recorded message to be read
+type replayRead struct {
+	frame *Frame
+	// writes is the number of messages written on the connection before the message was read
+	writes int
+}
+
+type replayConn struct {
+	replay *Replay
+	reads  []replayRead
+	// writeIDs are the IDs of the recorded written messages, nil for a message without an ID
+	writeIDs []json.RawMessage
+	last     bool
+	// previous is the time of the previous message read, for the pacing
+	previous time.Time
+
+	lock *sync.Mutex
+	// writes is the number of messages written during the replay
+	writes int
+	// ids maps the recorded request IDs to the ones written during the replay
+	ids     map[string]json.RawMessage
+	written chan struct{}
+	closed  chan struct{}
+	once    *sync.Once
+}
+
+func newReplayConn(replay *Replay, frames []*Frame, last bool) *replayConn {
+	c := &replayConn{
+		replay:  replay,
+		last:    last,
+		lock:    &sync.Mutex{},
+		ids:     make(map[string]json.RawMessage),
+		written: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		once:    &sync.Once{},
+	}
+
+	for _, frame := range frames {
+		switch frame.Type {
+		case FrameOpen:
+			c.previous = frame.Time
+		case FrameRead:
+			c.reads = append(c.reads, replayRead{frame: frame, writes: len(c.writeIDs)})
+		case FrameWrite:
+			c.writeIDs = append(c.writeIDs, messageID(frame.message()))
+		}
+	}
+
+	if c.previous.IsZero() && len(c.reads) > 0 {
+		c.previous = c.reads[0].frame.Time
+	}
+
+	return c
+}
+
+// messageID returns the raw ID of a JSON-RPC message, or nil if it has none
+func messageID(message []byte) json.RawMessage {
+	// most messages are notifications without an ID, which aren't decoded
+	if !bytes.Contains(message, []byte(`"id"`)) {
+		return nil
+	}
+
+	var m struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(message, &m); err != nil || string(m.ID) == "null" {
+		return nil
+	}
+
+	return m.ID
+}
+
+func (c *replayConn) ReadMessage(ctx context.Context) ([]byte, error) {
+	if len(c.reads) == 0 {
+		if !c.last {
+			_ = c.Close()
+			return nil, ErrAlreadyClosed
+		}
+
+		c.replay.finish()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, ErrAlreadyClosed
+		}
+	}
+
+	read := c.reads[0]
+
+	// wait until the client wrote the requests the message may answer
+	for {
+		c.lock.Lock()
+		writes := c.writes
+		c.lock.Unlock()
+
+		if writes >= read.writes {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, ErrAlreadyClosed
+		case <-c.written:
+		}
+	}
+
+	if err := c.pace(ctx, read.frame.Time); err != nil {
+		return nil, err
+	}
+
+	c.reads = c.reads[1:]
+
+	return c.replaceID(read.frame.message()), nil
+}
+
+// pace waits for the next message according to the replay options
+func (c *replayConn) pace(ctx context.Context, at time.Time) error {
+	defer func() { c.previous = at }()
+
+	var wait <-chan time.Time
+	switch {
+	case c.replay.opts.Step:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.closed:
+			return ErrAlreadyClosed
+		case <-c.replay.steps:
+			return nil
+		}
+	case c.replay.opts.Speed > 0:
+		delay := time.Duration(float64(at.Sub(c.previous)) / c.replay.opts.Speed)
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		wait = timer.C
+	default:
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return ErrAlreadyClosed
+	case <-wait:
+		return nil
+	}
+}
+
+// replaceID replaces the recorded ID of a response with the ID of the request written during the replay
+func (c *replayConn) replaceID(message []byte) []byte {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.ids) == 0 {
+		return message
+	}
+
+	id := messageID(message)
+	if id == nil {
+		return message
+	}
+
+	replacement, ok := c.ids[string(id)]
+	if !ok {
+		return message
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(message, &m); err != nil {
+		return message
+	}
+	m["id"] = replacement
+
+	replaced, err := json.Marshal(m)
+	if err != nil {
+		return message
+	}
+
+	return replaced
+}
+
+func (c *replayConn) WriteJSON(_ context.Context, v interface{}) error {
+	select {
+	case <-c.closed:
+		return ErrAlreadyClosed
+	default:
+	}
+
+	message, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	c.lock.Lock()
+	if c.writes < len(c.writeIDs) {
+		if recorded, id := c.writeIDs[c.writes], messageID(message); recorded != nil && id != nil {
+			c.ids[string(recorded)] = id
+		}
+	}
+	c.writes++
+	c.lock.Unlock()
+
+	select {
+	case c.written <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (c *replayConn) WritePriorityJSON(ctx context.Context, v interface{}) error {
+	return c.WriteJSON(ctx, v)
+}
+
+func (c *replayConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+
+	return nil
+}
+
+// finish marks the replay as done
+func (r *Replay) finish() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.done:
+	default:
+		close(r.done)
+	}
+}
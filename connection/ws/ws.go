@@ -4,13 +4,28 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
 	"time"
 )
 
-const DefaultHandshakeTimeout = 15 * time.Second
+const (
+	DefaultHandshakeTimeout = 15 * time.Second
+	DefaultPingInterval     = 15 * time.Second
+	DefaultPongTimeout      = 10 * time.Second
+	DefaultWriteTimeout     = 10 * time.Second
+)
+
+var (
+	// ErrAlreadyClosed is returned when trying to read/write from/to closed connection.
+	ErrAlreadyClosed = errors.New("websocket connection is closed")
 
-// ErrAlreadyClosed is returned when trying to read/write from/to closed connection.
-var ErrAlreadyClosed = errors.New("websocket connection is closed")
+	// ErrStale is returned when the connection is closed because the server stopped
+	// answering pings or no message was received within the idle timeout.
+	ErrStale = errors.New("websocket connection is stale")
+)
 
 // Conn provides interface for websocket connection.
 type Conn interface {
@@ -19,8 +34,58 @@ type Conn interface {
 	Close() error
 }
 
+// PriorityWriter is implemented by connections which can write a message ahead of the messages waiting to be written
+type PriorityWriter interface {
+	WritePriorityJSON(context.Context, interface{}) error
+}
+
 // DialOptions represents Dial's options.
 type DialOptions struct {
 	HandshakeTimeout time.Duration
-	TLSClientConfig  *tls.Config
+
+	// TLSClientConfig is the TLS configuration of wss:// connections.
+	// Default: TLS 1.2 or later, verified against the system roots
+	TLSClientConfig *tls.Config
+
+	// PingInterval is how often a ping is sent to the server.
+	// Default: DefaultPingInterval, a negative value disables pings.
+	PingInterval time.Duration
+
+	// PongTimeout is how long the server may take to answer a ping before
+	// the connection is considered stale.
+	// Default: DefaultPongTimeout
+	PongTimeout time.Duration
+
+	// IdleTimeout closes the connection as stale when no message is received for this long,
+	// even if the server answers pings. Pick a value above the longest expected gap between feed messages.
+	// Default: disabled
+	IdleTimeout time.Duration
+
+	// WriteTimeout is how long a single write may block before the connection is considered broken.
+	// Default: DefaultWriteTimeout
+	WriteTimeout time.Duration
+
+	// Proxy returns the proxy for the handshake request, e.g. http.ProxyFromEnvironment.
+	// HTTP CONNECT ("http://") and SOCKS5 ("socks5://") proxies are supported.
+	// Default: no proxy
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// NetDialContext dials the TCP connection to the server, or to the proxy.
+	// Default: net.Dialer
+	NetDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// Logger logs why the connection is closed, e.g. because it is stale or its write queue is full.
+	// Default: no logging
+	Logger *slog.Logger
 }
+
+// discardHandler is a slog.Handler which drops every record
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool { return false }
+
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h discardHandler) WithGroup(string) slog.Handler { return h }
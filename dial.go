@@ -0,0 +1,203 @@
+package bloxroute_sdk_go
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+var ErrUnsupportedProxy = errors.New("proxy scheme must be http, socks5 or socks5h")
+
+// DialFunc is the function used to dial the TCP connections to the endpoints and proxies
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Dial lets DialFunc be used as a proxy.Dialer
+func (f DialFunc) Dial(network, address string) (net.Conn, error) {
+	return f(context.Background(), network, address)
+}
+
+// DialContext lets DialFunc be used as a proxy.ContextDialer
+func (f DialFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+// customDial reports whether the connections are dialed other than with the defaults of each transport
+func (c *Config) customDial() bool {
+	return c.Proxy != nil || c.LocalAddr != "" || c.LocalInterface != "" || c.DialContext != nil
+}
+
+// netDialContext dials the TCP connections to the endpoints or to the proxy, from the local address if set
+func (c *Config) netDialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if c.DialContext != nil {
+		return c.DialContext(ctx, network, address)
+	}
+
+	ip, err := c.localIP()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{}
+	if ip != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+// localIP returns the IP address of LocalAddr or LocalInterface, or nil to let the OS choose it.
+// The address of the interface is looked up on every dial as it may change while the client runs.
+func (c *Config) localIP() (net.IP, error) {
+	if c.LocalAddr != "" {
+		return net.ParseIP(c.LocalAddr), nil
+	}
+
+	if c.LocalInterface == "" {
+		return nil, nil
+	}
+
+	iface, err := net.InterfaceByName(c.LocalInterface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interface %s: %w", c.LocalInterface, err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses of interface %s: %w", c.LocalInterface, err)
+	}
+
+	// IPv4 is preferred as most endpoints are reached over IPv4
+	var ip net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+
+		if ip == nil {
+			ip = ipNet.IP
+		}
+	}
+
+	if ip == nil {
+		return nil, fmt.Errorf("interface %s has no address", c.LocalInterface)
+	}
+
+	return ip, nil
+}
+
+// grpcDialer returns the dialer of a gRPC endpoint, which goes through the proxy if any.
+// The proxy is picked as for an https URL if the endpoint uses TLS, and as for an http URL otherwise.
+// Without Proxy, it is taken from HTTPS_PROXY as gRPC does when it dials by itself.
+func (c *Config) grpcDialer(secure bool) func(context.Context, string) (net.Conn, error) {
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	proxyFunc := c.Proxy
+	if proxyFunc == nil {
+		proxyFunc = http.ProxyFromEnvironment
+		scheme = "https"
+	}
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		proxyURL, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: scheme, Host: address}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proxy for %s: %w", address, err)
+		}
+
+		if proxyURL == nil {
+			return c.netDialContext(ctx, "tcp", address)
+		}
+
+		return dialProxy(ctx, proxyURL, address, c.netDialContext)
+	}
+}
+
+// dialProxy connects to address through the HTTP CONNECT or SOCKS5 proxy
+func dialProxy(ctx context.Context, proxyURL *url.URL, address string, forward DialFunc) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "http":
+		return dialHTTPProxy(ctx, proxyURL, address, forward)
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+
+		dialer, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, forward)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SOCKS5 dialer: %w", err)
+		}
+
+		return dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProxy, proxyURL.Scheme)
+	}
+}
+
+// dialHTTPProxy opens a tunnel to address with a CONNECT request to the proxy
+func dialHTTPProxy(ctx context.Context, proxyURL *url.URL, address string, forward DialFunc) (net.Conn, error) {
+	proxyAddress := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyAddress = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+
+	conn, err := forward(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxyAddress, err)
+	}
+
+	// the deadline of ctx applies to the CONNECT request, not to the tunnel
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT request to proxy %s: %w", proxyAddress, err)
+	}
+
+	// the endpoint doesn't send anything before the client does, so nothing is lost with the buffered reader
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read CONNECT response from proxy %s: %w", proxyAddress, err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxyAddress, address, strings.TrimSpace(resp.Status))
+	}
+
+	return conn, nil
+}
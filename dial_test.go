@@ -0,0 +1,213 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tunnel copies the data between the client and the target until one of them closes the connection
+func tunnel(client, target net.Conn) {
+	defer client.Close()
+	defer target.Close()
+
+	go func() { _, _ = io.Copy(target, client) }()
+	_, _ = io.Copy(client, target)
+}
+
+// testHTTPProxy starts an HTTP CONNECT proxy and counts the tunnels it opens
+func testHTTPProxy(t *testing.T, tunnels *atomic.Int32) *url.URL {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") == "" {
+			http.Error(w, "proxy authentication required", http.StatusProxyAuthRequired)
+			return
+		}
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			_ = target.Close()
+			return
+		}
+
+		tunnels.Add(1)
+		_, _ = client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		tunnel(client, target)
+	}))
+	t.Cleanup(server.Close)
+
+	proxyURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	proxyURL.User = url.UserPassword("user", "password")
+
+	return proxyURL
+}
+
+// testSOCKS5Proxy starts a SOCKS5 proxy without authentication and counts the tunnels it opens
+func testSOCKS5Proxy(t *testing.T, tunnels *atomic.Int32) *url.URL {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	serve := func(client net.Conn) {
+		// greeting: version, number of methods, methods
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(client, greeting); err != nil {
+			_ = client.Close()
+			return
+		}
+		if _, err := io.ReadFull(client, make([]byte, greeting[1])); err != nil {
+			_ = client.Close()
+			return
+		}
+		_, _ = client.Write([]byte{5, 0})
+
+		// request: version, command, reserved, address type, address, port
+		request := make([]byte, 4)
+		if _, err := io.ReadFull(client, request); err != nil {
+			_ = client.Close()
+			return
+		}
+
+		var host string
+		switch request[3] {
+		case 1:
+			ip := make([]byte, net.IPv4len)
+			_, _ = io.ReadFull(client, ip)
+			host = net.IP(ip).String()
+		case 3:
+			length := make([]byte, 1)
+			_, _ = io.ReadFull(client, length)
+			name := make([]byte, length[0])
+			_, _ = io.ReadFull(client, name)
+			host = string(name)
+		default:
+			_ = client.Close()
+			return
+		}
+
+		port := make([]byte, 2)
+		_, _ = io.ReadFull(client, port)
+
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+		if err != nil {
+			_, _ = client.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			_ = client.Close()
+			return
+		}
+
+		tunnels.Add(1)
+		_, _ = client.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		tunnel(client, target)
+	}
+
+	go func() {
+		for {
+			client, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(client)
+		}
+	}()
+
+	return &url.URL{Scheme: "socks5", Host: listener.Addr().String()}
+}
+
+func TestDialProxy(t *testing.T) {
+	proxies := map[string]func(*testing.T, *atomic.Int32) *url.URL{
+		"http":   testHTTPProxy,
+		"socks5": testSOCKS5Proxy,
+	}
+
+	for name, proxy := range proxies {
+		t.Run(name, func(t *testing.T) {
+			t.Run("ws", func(t *testing.T) {
+				var tunnels atomic.Int32
+				c, err := NewClient(context.Background(), &Config{
+					WSGatewayURL: testEventsServer(t),
+					AuthHeader:   "auth",
+					Proxy:        http.ProxyURL(proxy(t, &tunnels)),
+				})
+				require.NoError(t, err)
+				require.EqualValues(t, 1, tunnels.Load())
+				require.NoError(t, c.Close())
+			})
+
+			t.Run("grpc", func(t *testing.T) {
+				var tunnels atomic.Int32
+				c, err := NewClient(context.Background(), &Config{
+					GRPCGatewayURL: testGRPCGateway(t, &testGatewayServer{}),
+					AuthHeader:     "auth",
+					Proxy:          http.ProxyURL(proxy(t, &tunnels)),
+				})
+				require.NoError(t, err)
+				defer func() { require.NoError(t, c.Close()) }()
+
+				res, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+				require.NoError(t, err)
+				require.JSONEq(t, `{"tx_hash":"0xf86b"}`, string(*res))
+				require.EqualValues(t, 1, tunnels.Load())
+			})
+		})
+	}
+}
+
+func TestDialLocalAddr(t *testing.T) {
+	t.Run("local_addr", func(t *testing.T) {
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, &testGatewayServer{}),
+			AuthHeader:     "auth",
+			LocalAddr:      "127.0.0.1",
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid_local_addr", func(t *testing.T) {
+		_, err := NewClient(context.Background(), &Config{
+			WSGatewayURL: "ws://127.0.0.1:1",
+			AuthHeader:   "auth",
+			LocalAddr:    "eth0",
+		})
+		require.ErrorIs(t, err, ErrInvalidLocalAddr)
+	})
+
+	t.Run("dial_context", func(t *testing.T) {
+		var dials atomic.Int32
+		dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+			dials.Add(1)
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+
+		c, err := NewClient(context.Background(), &Config{
+			WSGatewayURL: testEventsServer(t),
+			AuthHeader:   "auth",
+			DialContext:  dial,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, dials.Load())
+		require.NoError(t, c.Close())
+	})
+}
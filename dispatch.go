@@ -0,0 +1,206 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const defaultDispatchQueueSize = 1000
+
+// ErrSubscriptionOverflow is passed to the callback when the subscription is ended
+// because its queue overflowed with OverflowDisconnect
+var ErrSubscriptionOverflow = errors.New("subscription queue overflow")
+
+// OverflowPolicy decides what happens to a notification when the queue of its subscription is full
+type OverflowPolicy int
+
+// OverflowPolicy enumeration
+const (
+	// OverflowDropOldest drops the oldest queued notification to make room, so a slow callback never blocks the connection
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowBlock waits until there is room in the queue, which blocks the connection's reader,
+	// and with it the other subscriptions of the connection, until the callback catches up
+	OverflowBlock
+	// OverflowDropNewest drops the notification which doesn't fit
+	OverflowDropNewest
+	// OverflowDisconnect ends the subscription and passes ErrSubscriptionOverflow to the callback
+	OverflowDisconnect
+)
+
+// DispatchStats describes the dispatch queue of a subscription
+type DispatchStats struct {
+	// Subscription is the ID of the subscription
+	Subscription string
+
+	// Feed is the feed of the subscription
+	Feed types.FeedType
+
+	// Queued is the number of notifications waiting for the callback
+	Queued int
+
+	// Dropped is the number of notifications dropped because the queue was full, also counted by Metrics.NotificationDropped
+	Dropped uint64
+}
+
+// dispatchedNotification is a callback invocation waiting in the queue
+type dispatchedNotification struct {
+	ctx    context.Context
+	err    error
+	result any
+}
+
+// dispatcher passes the notifications of a subscription to its callback
+// on a dedicated goroutine, so a slow callback doesn't hold up other subscriptions
+type dispatcher struct {
+	id         string
+	feed       types.FeedType
+	callback   CallbackFunc[any]
+	metrics    Metrics
+	policy     OverflowPolicy
+	queue      chan dispatchedNotification
+	dropped    atomic.Uint64
+	overflow   chan struct{}
+	onOverflow func()
+	stop       chan struct{}
+	// overflowOnce makes sure the subscription is ended only once
+	overflowOnce *sync.Once
+	closeOnce    *sync.Once
+}
+
+func newDispatcher(config *Config, id string, feed types.FeedType, callback CallbackFunc[any], onOverflow func()) *dispatcher {
+	d := &dispatcher{
+		id:           id,
+		feed:         feed,
+		callback:     callback,
+		metrics:      config.metrics(),
+		policy:       config.OverflowPolicy,
+		queue:        make(chan dispatchedNotification, config.DispatchQueueSize),
+		overflow:     make(chan struct{}),
+		onOverflow:   onOverflow,
+		stop:         make(chan struct{}),
+		overflowOnce: &sync.Once{},
+		closeOnce:    &sync.Once{},
+	}
+
+	go d.run()
+
+	return d
+}
+
+// dispatch queues the notification according to the overflow policy
+func (d *dispatcher) dispatch(ctx context.Context, err error, result any) {
+	n := dispatchedNotification{ctx: ctx, err: err, result: result}
+	defer func() { d.metrics.QueueDepth(string(d.feed), len(d.queue)) }()
+
+	switch d.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case d.queue <- n:
+				return
+			case <-d.stop:
+				return
+			default:
+			}
+
+			select {
+			case <-d.queue:
+				d.drop(1)
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		select {
+		case d.queue <- n:
+		case <-d.stop:
+		default:
+			d.drop(1)
+		}
+	case OverflowDisconnect:
+		select {
+		case <-d.overflow:
+			// the subscription is being ended
+			d.drop(1)
+			return
+		default:
+		}
+
+		select {
+		case d.queue <- n:
+		case <-d.stop:
+		default:
+			d.drop(1)
+			d.overflowOnce.Do(func() {
+				close(d.overflow)
+				go d.onOverflow()
+			})
+		}
+	default:
+		select {
+		case d.queue <- n:
+		case <-d.stop:
+		}
+	}
+}
+
+// run passes the queued notifications to the callback until the dispatcher is closed
+func (d *dispatcher) run() {
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-d.overflow:
+			d.endOverflowed(0)
+			return
+		case n := <-d.queue:
+			select {
+			case <-d.overflow:
+				// the subscription has ended while the notification was waiting
+				d.endOverflowed(1)
+				return
+			default:
+			}
+
+			start := time.Now()
+			d.callback(n.ctx, n.err, n.result)
+			d.metrics.CallbackDuration(string(d.feed), time.Since(start))
+		}
+	}
+}
+
+// endOverflowed drops the queued notifications and tells the callback the subscription has ended
+func (d *dispatcher) endOverflowed(taken int) {
+	d.drop(taken + len(d.queue))
+	d.callback(context.Background(), ErrSubscriptionOverflow, nil)
+}
+
+// drop counts the dropped notifications
+func (d *dispatcher) drop(n int) {
+	d.dropped.Add(uint64(n))
+	for range n {
+		d.metrics.NotificationDropped(string(d.feed))
+	}
+}
+
+// stats returns the current state of the queue
+func (d *dispatcher) stats() DispatchStats {
+	return DispatchStats{
+		Subscription: d.id,
+		Feed:         d.feed,
+		Queued:       len(d.queue),
+		Dropped:      d.dropped.Load(),
+	}
+}
+
+// close stops the dispatcher and drops the queued notifications.
+// It doesn't wait for a running callback, so it is safe to call from the callback.
+func (d *dispatcher) close() {
+	d.closeOnce.Do(func() {
+		close(d.stop)
+	})
+}
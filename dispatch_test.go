@@ -0,0 +1,171 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+func TestDispatch(t *testing.T) {
+	// newBlockedDispatcher returns a dispatcher with a queue of two notifications
+	// whose callback is blocked on the first notification until release is closed
+	newBlockedDispatcher := func(policy OverflowPolicy, onOverflow func()) (*dispatcher, chan any, chan error, chan struct{}) {
+		results := make(chan any, 10)
+		errs := make(chan error, 10)
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+
+		d := newDispatcher(&Config{DispatchQueueSize: 2, OverflowPolicy: policy}, "1", types.NewTxsFeed, func(ctx context.Context, err error, result any) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			if err != nil {
+				errs <- err
+				return
+			}
+			results <- result
+		}, onOverflow)
+
+		d.dispatch(context.Background(), nil, 0)
+		<-started
+
+		return d, results, errs, release
+	}
+
+	receive := func(t *testing.T, results chan any, count int) []any {
+		received := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			select {
+			case result := <-results:
+				received = append(received, result)
+			case <-time.After(time.Second):
+				t.Fatalf("received %d of %d notifications", len(received), count)
+			}
+		}
+
+		return received
+	}
+
+	t.Run("drop_newest", func(t *testing.T) {
+		d, results, _, release := newBlockedDispatcher(OverflowDropNewest, nil)
+		defer d.close()
+
+		for i := 1; i <= 4; i++ {
+			d.dispatch(context.Background(), nil, i)
+		}
+
+		stats := d.stats()
+		require.Equal(t, "1", stats.Subscription)
+		require.Equal(t, types.NewTxsFeed, stats.Feed)
+		require.Equal(t, 2, stats.Queued)
+		require.Equal(t, uint64(2), stats.Dropped)
+
+		close(release)
+		require.Equal(t, []any{0, 1, 2}, receive(t, results, 3))
+	})
+
+	t.Run("drop_oldest", func(t *testing.T) {
+		d, results, _, release := newBlockedDispatcher(OverflowDropOldest, nil)
+		defer d.close()
+
+		for i := 1; i <= 4; i++ {
+			d.dispatch(context.Background(), nil, i)
+		}
+
+		require.Equal(t, uint64(2), d.stats().Dropped)
+
+		close(release)
+		require.Equal(t, []any{0, 3, 4}, receive(t, results, 3))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		metrics := newTestMetrics()
+		release := make(chan struct{})
+		d := newDispatcher(&Config{DispatchQueueSize: 1, Metrics: metrics}, "1", types.NewTxsFeed, func(context.Context, error, any) {
+			<-release
+		}, nil)
+		defer d.close()
+		defer close(release)
+
+		// the default policy never blocks, and the callback holds at most one of the notifications,
+		// so at least two of them don't fit in the queue
+		for i := 0; i < 4; i++ {
+			d.dispatch(context.Background(), nil, i)
+		}
+
+		dropped := d.stats().Dropped
+		require.GreaterOrEqual(t, dropped, uint64(2))
+		require.Equal(t, int(dropped), metrics.count("dropped:"+string(types.NewTxsFeed)))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		d, results, _, release := newBlockedDispatcher(OverflowBlock, nil)
+		defer d.close()
+
+		d.dispatch(context.Background(), nil, 1)
+		d.dispatch(context.Background(), nil, 2)
+
+		dispatched := make(chan struct{})
+		go func() {
+			defer close(dispatched)
+			d.dispatch(context.Background(), nil, 3)
+		}()
+
+		select {
+		case <-dispatched:
+			t.Fatal("dispatch didn't block on a full queue")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		<-dispatched
+		require.Equal(t, []any{0, 1, 2, 3}, receive(t, results, 4))
+		require.Zero(t, d.stats().Dropped)
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		overflowed := make(chan struct{})
+		d, results, errs, release := newBlockedDispatcher(OverflowDisconnect, func() { close(overflowed) })
+		defer d.close()
+
+		for i := 1; i <= 4; i++ {
+			d.dispatch(context.Background(), nil, i)
+		}
+
+		select {
+		case <-overflowed:
+		case <-time.After(time.Second):
+			t.Fatal("subscription wasn't ended on overflow")
+		}
+
+		close(release)
+		require.Equal(t, []any{0}, receive(t, results, 1))
+
+		select {
+		case err := <-errs:
+			require.ErrorIs(t, err, ErrSubscriptionOverflow)
+		case <-time.After(time.Second):
+			t.Fatal("callback didn't receive the overflow error")
+		}
+
+		require.Equal(t, uint64(4), d.stats().Dropped)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		d, _, _, release := newBlockedDispatcher(OverflowBlock, nil)
+		defer close(release)
+
+		d.dispatch(context.Background(), nil, 1)
+		d.dispatch(context.Background(), nil, 2)
+		d.close()
+
+		// dispatching to a closed dispatcher must not block
+		d.dispatch(context.Background(), nil, 3)
+	})
+}
@@ -1,11 +1,16 @@
 package bloxroute_sdk_go
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
 
+// ErrConnectionLost matches every ConnectionLostError with errors.Is
+var ErrConnectionLost = errors.New("connection lost")
+
 type RPCError jsonrpc2.Error
 
 // Error implements the Go error interface.
@@ -23,3 +28,44 @@ func (e *RPCError) Error() string {
 
 	return fmt.Sprintf("code: %v message: %s, data: %s", e.Code, e.Message, string(b))
 }
+
+// Is reports whether target is ErrRateLimited and the endpoint rejected the request for exceeding the account's limits
+func (e *RPCError) Is(target error) bool {
+	if target != ErrRateLimited || e == nil {
+		return false
+	}
+
+	message := strings.ToLower(e.Message)
+	for _, s := range []string{"rate limit", "too many requests", "quota", "limit exceeded", "exceeded the limit"} {
+		if strings.Contains(message, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConnectionLostError is returned for a request which was in flight when the connection to its endpoint was lost.
+// The request may or may not have been processed by the endpoint.
+type ConnectionLostError struct {
+	// Endpoint is the URL of the endpoint the connection to which was lost
+	Endpoint string
+
+	// Err is the cause of the connection loss
+	Err error
+}
+
+// Error implements the Go error interface.
+func (e *ConnectionLostError) Error() string {
+	return fmt.Sprintf("connection to %s lost: %s", e.Endpoint, e.Err)
+}
+
+// Unwrap returns the cause of the connection loss
+func (e *ConnectionLostError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrConnectionLost
+func (e *ConnectionLostError) Is(target error) bool {
+	return target == ErrConnectionLost
+}
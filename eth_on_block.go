@@ -3,6 +3,7 @@ package bloxroute_sdk_go
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -84,12 +85,19 @@ type OnBlockParamsBlockNumber struct {
 }
 
 // OnBlock subscribes to stream of changes in the EVM state when a new block is mined
-func (c *Client) OnBlock(ctx context.Context, params *OnBlockParams, callbackFunc CallbackFunc[*OnBlockNotification]) error {
+func (c *Client) OnBlock(ctx context.Context, params *OnBlockParams, callbackFunc CallbackFunc[*OnBlockNotification], opts ...CallOption) error {
+	_, err := c.SubscribeEthOnBlock(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribeEthOnBlock subscribes to the eth_onBlock feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribeEthOnBlock(ctx context.Context, params *OnBlockParams, callbackFunc CallbackFunc[*OnBlockNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
-		return ErrNilParams
+		return nil, ErrNilParams
 	}
 	if len(params.CallParams) == 0 {
-		return fmt.Errorf("at least one call_params is required")
+		return nil, fmt.Errorf("at least one call_params is required")
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
@@ -100,10 +108,26 @@ func (c *Client) OnBlock(ctx context.Context, params *OnBlockParams, callbackFun
 		callbackFunc(ctx, err, result.(*OnBlockNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.OnBlockFeed, params, wrap)
+	return c.subscribe(ctx, types.OnBlockFeed, params, wrap, opts)
+}
+
+// OnBlockChan subscribes to the eth_onBlock feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnBlockChan(ctx context.Context, params *OnBlockParams, opts ...CallOption) (<-chan Notification[*OnBlockNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeEthOnBlock(ctx, params, callback, opts...)
+	})
+}
+
+// OnBlockSeq returns an iterator over the notifications of the eth_onBlock feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnBlockSeq(ctx context.Context, params *OnBlockParams, opts ...CallOption) iter.Seq2[*OnBlockNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeEthOnBlock(ctx, params, callback, opts...)
+	})
 }
 
-// UnsubscribeFromEthOnBlock unsubscribes from the eth_onBlock feed
+// UnsubscribeFromEthOnBlock ends all subscriptions to the eth_onBlock feed
 func (c *Client) UnsubscribeFromEthOnBlock() error {
 	return c.handler.UnsubscribeRetry(types.OnBlockFeed)
 }
@@ -0,0 +1,112 @@
+package bloxroute_sdk_go
+
+import (
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// State is the state of the connection to an endpoint
+type State int
+
+// State enumeration
+const (
+	// StateConnecting is the state before the first connection is established
+	StateConnecting State = iota
+	// StateConnected is the state while the connection is established
+	StateConnected
+	// StateDisconnected is the state after the connection is lost when it is not reconnected
+	StateDisconnected
+	// StateReconnecting is the state while the lost connection is being reconnected
+	StateReconnecting
+	// StateClosed is the state after the client is closed
+	StateClosed
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// EventType is the type of a connection lifecycle event
+type EventType int
+
+// EventType enumeration
+const (
+	// EventConnected is emitted when the connection is established, including after a reconnect
+	EventConnected EventType = iota
+	// EventDisconnected is emitted when the connection is lost, with the cause in Err
+	EventDisconnected
+	// EventReconnecting is emitted before each reconnect attempt, with the attempt number in Attempt.
+	// When a single gRPC stream is reopened, Feed and Subscription are set as well
+	EventReconnecting
+	// EventResubscribed is emitted for each subscription renewed after a reconnect, with the failure in Err
+	EventResubscribed
+	// EventClosed is emitted when the connection is closed
+	EventClosed
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventConnected:
+		return "connected"
+	case EventDisconnected:
+		return "disconnected"
+	case EventReconnecting:
+		return "reconnecting"
+	case EventResubscribed:
+		return "resubscribed"
+	case EventClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a connection lifecycle event
+type Event struct {
+	// Type is the type of the event
+	Type EventType
+
+	// Endpoint is the URL of the endpoint the event relates to
+	Endpoint string
+
+	// Time is when the event occurred
+	Time time.Time
+
+	// Err is the cause of EventDisconnected, or the failure of EventResubscribed
+	Err error
+
+	// Attempt is the number of the reconnect attempt of EventReconnecting, starting from 1
+	Attempt int
+
+	// Feed is the feed of the subscription of EventResubscribed, or of the reopened gRPC stream of EventReconnecting
+	Feed types.FeedType
+
+	// Subscription is the ID of the subscription of EventResubscribed, or of the reopened gRPC stream of EventReconnecting
+	Subscription string
+}
+
+// emit passes the event to the OnEvent hook, if any
+func (c *Config) emit(e Event) {
+	if c.OnEvent == nil {
+		return
+	}
+
+	e.Time = time.Now()
+	c.OnEvent(e)
+}
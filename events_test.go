@@ -0,0 +1,168 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fastjson"
+	"google.golang.org/grpc"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testEventsServer starts a websocket server which confirms subscriptions
+// and drops the first connection right after the first subscription
+func testEventsServer(t *testing.T) string {
+	t.Helper()
+
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		connection := connections.Add(1)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			v, err := fastjson.ParseBytes(message)
+			if err != nil || string(v.GetStringBytes("method")) != "subscribe" {
+				continue
+			}
+
+			response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":"subscription-%d"}`, v.GetStringBytes("id"), connection)
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(response)); err != nil {
+				return
+			}
+
+			if connection == 1 {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestEvents(t *testing.T) {
+	events := make(chan Event, 100)
+	url := testEventsServer(t)
+
+	c, err := NewClient(context.Background(), &Config{
+		WSGatewayURL: url,
+		AuthHeader:   "auth",
+		OnEvent:      func(e Event) { events <- e },
+	})
+	require.NoError(t, err)
+	require.Equal(t, StateConnected, c.State())
+
+	next := func(t *testing.T) Event {
+		t.Helper()
+
+		select {
+		case e := <-events:
+			require.Equal(t, url, e.Endpoint)
+			require.False(t, e.Time.IsZero())
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for event")
+			return Event{}
+		}
+	}
+
+	require.Equal(t, EventConnected, next(t).Type)
+
+	sub, err := c.SubscribeNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+	require.NoError(t, err)
+
+	e := next(t)
+	require.Equal(t, EventDisconnected, e.Type)
+	require.Error(t, e.Err)
+
+	e = next(t)
+	require.Equal(t, EventReconnecting, e.Type)
+	require.Equal(t, 1, e.Attempt)
+
+	require.Equal(t, EventConnected, next(t).Type)
+
+	e = next(t)
+	require.Equal(t, EventResubscribed, e.Type)
+	require.Equal(t, types.NewTxsFeed, e.Feed)
+	require.Equal(t, sub.ID(), e.Subscription)
+	require.NoError(t, e.Err)
+	require.Equal(t, StateConnected, c.State())
+
+	require.NoError(t, c.Close())
+	require.Equal(t, EventClosed, next(t).Type)
+	require.Equal(t, StateClosed, c.State())
+}
+
+func TestGRPCEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterGatewayServer(server, &testGatewayServer{})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	events := make(chan Event, 100)
+	c, err := NewClient(context.Background(), &Config{
+		GRPCGatewayURL: "grpc://" + listener.Addr().String(),
+		AuthHeader:     "auth",
+		OnEvent:        func(e Event) { events <- e },
+		// the connection goes idle between the calls
+		GRPCDialOptions: []grpc.DialOption{grpc.WithIdleTimeout(100 * time.Millisecond)},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	next := func(t *testing.T) Event {
+		t.Helper()
+
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for event")
+			return Event{}
+		}
+	}
+
+	_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+	require.NoError(t, err)
+	require.Equal(t, EventConnected, next(t).Type)
+
+	// going idle and connecting again on the next call isn't a connection loss
+	time.Sleep(500 * time.Millisecond)
+	_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// the disconnect is reported before any reconnect attempt
+	server.Stop()
+	_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+	require.Error(t, err)
+
+	e := next(t)
+	require.Equal(t, EventDisconnected, e.Type)
+	require.Error(t, e.Err)
+}
@@ -0,0 +1,176 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+)
+
+// EndpointResult is the outcome of a request sent to a single endpoint
+type EndpointResult struct {
+	// Endpoint is the URL of the endpoint
+	Endpoint string
+
+	// Result is the response of the endpoint, nil if the request failed
+	Result *json.RawMessage
+
+	// Err is the error returned by the endpoint
+	Err error
+
+	// Latency is the time it took the endpoint to respond
+	Latency time.Duration
+}
+
+// FanOutResult is the outcome of a request sent to all endpoints in parallel
+type FanOutResult struct {
+	// Endpoint is the endpoint which succeeded first
+	Endpoint string
+
+	// Result is the response of the endpoint which succeeded first
+	Result *json.RawMessage
+
+	results []EndpointResult
+	lock    *sync.Mutex
+	done    chan struct{}
+}
+
+// Results returns the results of the endpoints which have responded so far
+func (r *FanOutResult) Results() []EndpointResult {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	results := make([]EndpointResult, len(r.results))
+	copy(results, r.results)
+
+	return results
+}
+
+// Wait blocks until all endpoints have responded and returns their results
+func (r *FanOutResult) Wait() []EndpointResult {
+	<-r.done
+
+	return r.Results()
+}
+
+type endpointHandler struct {
+	endpoint string
+	handler  handler
+	// err is the failure to connect to the endpoint, when there is no handler
+	err error
+}
+
+// endpointHandlers returns the handlers of all endpoints, connecting those which aren't connected yet
+func (c *Client) endpointHandlers() []endpointHandler {
+	if h, ok := c.handler.(*multiHandler); ok {
+		return h.all()
+	}
+
+	return []endpointHandler{{endpoint: c.endpoint, handler: c.handler}}
+}
+
+// fanOut sends the request to all endpoints in parallel and returns as soon
+// as one of them succeeds. If all of them fail, the errors of all endpoints are returned.
+func (c *Client) fanOut(ctx context.Context, method jsonrpc.RPCRequestType, params any, opts []CallOption) (_ *FanOutResult, err error) {
+	ctx, span := c.startSpan(ctx, method, attribute.Bool("bloxroute.fan_out", true))
+	defer func() { endSpan(span, err) }()
+
+	handlers := c.endpointHandlers()
+
+	// the request goes to every endpoint, so the endpoint preference doesn't apply
+	ctx, o := c.withCallOptions(ctx, opts)
+	ctx, cancel := o.withDeadline(ctx)
+
+	if err := c.limiter.request(ctx, method); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res := &FanOutResult{
+		results: make([]EndpointResult, 0, len(handlers)),
+		lock:    &sync.Mutex{},
+		done:    make(chan struct{}),
+	}
+
+	first := make(chan EndpointResult, 1)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(handlers))
+	for _, h := range handlers {
+		go func(h endpointHandler) {
+			defer wg.Done()
+
+			start := time.Now()
+
+			// the request to each endpoint has a span of its own, carrying the endpoint and its JSON-RPC ID
+			ctx, span := c.startSpan(ctx, method)
+
+			var result *json.RawMessage
+			err := h.err
+			if h.handler != nil {
+				err = o.do(ctx, func() error {
+					var err error
+					result, err = h.handler.Request(ctx, method, params)
+					return err
+				})
+			}
+			endSpan(span, err)
+			endpointResult := EndpointResult{
+				Endpoint: h.endpoint,
+				Result:   result,
+				Err:      err,
+				Latency:  time.Since(start),
+			}
+
+			res.lock.Lock()
+			res.results = append(res.results, endpointResult)
+			res.lock.Unlock()
+
+			if err == nil {
+				select {
+				case first <- endpointResult:
+				default:
+				}
+			}
+		}(h)
+	}
+
+	go func() {
+		wg.Wait()
+		// the other endpoints may still be responding when the first success is returned
+		cancel()
+		close(res.done)
+	}()
+
+	select {
+	case success := <-first:
+		res.Endpoint = success.Endpoint
+		res.Result = success.Result
+
+		return res, nil
+	case <-res.done:
+	}
+
+	// all endpoints have responded, but the first success may have arrived at the same time
+	select {
+	case success := <-first:
+		res.Endpoint = success.Endpoint
+		res.Result = success.Result
+
+		return res, nil
+	default:
+	}
+
+	errs := make([]error, 0, len(handlers))
+	for _, result := range res.Results() {
+		errs = append(errs, fmt.Errorf("%s: %w", result.Endpoint, result.Err))
+	}
+
+	return res, fmt.Errorf("%s failed on all endpoints: %w", method, errors.Join(errs...))
+}
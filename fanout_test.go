@@ -0,0 +1,130 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testRequestHandler responds to every request with the same result after a delay
+type testRequestHandler struct {
+	result string
+	err    error
+	delay  time.Duration
+}
+
+func (h *testRequestHandler) Type() handlerSourceType { return handlerSourceTypeGatewayWS }
+
+func (h *testRequestHandler) Subscribe(context.Context, string, types.FeedType, any, CallbackFunc[any]) error {
+	return nil
+}
+
+func (h *testRequestHandler) Request(ctx context.Context, _ jsonrpc.RPCRequestType, _ any) (*json.RawMessage, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(h.delay):
+	}
+
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	result := json.RawMessage(h.result)
+	return &result, nil
+}
+
+func (h *testRequestHandler) Unsubscribe(string) error { return nil }
+
+func (h *testRequestHandler) UnsubscribeRetry(types.FeedType) error { return nil }
+
+func (h *testRequestHandler) Healthy() bool { return true }
+
+func (h *testRequestHandler) State() State { return StateConnected }
+
+func (h *testRequestHandler) DispatchStats() []DispatchStats { return nil }
+
+func (h *testRequestHandler) Close() error { return nil }
+
+func testFanOutClient(handlers ...handler) *Client {
+	endpoints := make([]Endpoint, len(handlers))
+	for i := range handlers {
+		endpoints[i] = Endpoint{WSGatewayURL: string(rune('a' + i))}
+	}
+
+	return &Client{
+		handler: &multiHandler{
+			endpoints:  endpoints,
+			handlers:   handlers,
+			switchLock: &sync.RWMutex{},
+		},
+		blockchainNetwork: "BSC-Mainnet",
+		config:            &Config{},
+	}
+}
+
+func TestSendTxFanOut(t *testing.T) {
+	t.Run("first_success", func(t *testing.T) {
+		c := testFanOutClient(
+			&testRequestHandler{err: errors.New("rejected")},
+			&testRequestHandler{result: `{"tx_hash":"0x1"}`, delay: 10 * time.Millisecond},
+			&testRequestHandler{result: `{"tx_hash":"0x1"}`, delay: time.Second},
+		)
+
+		res, err := c.SendTxFanOut(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		require.Equal(t, "b", res.Endpoint)
+		require.JSONEq(t, `{"tx_hash":"0x1"}`, string(*res.Result))
+
+		results := res.Wait()
+		require.Len(t, results, 3)
+		require.EqualError(t, results[0].Err, "rejected")
+		require.Equal(t, "c", results[2].Endpoint)
+	})
+
+	t.Run("all_failed", func(t *testing.T) {
+		c := testFanOutClient(
+			&testRequestHandler{err: errors.New("rejected")},
+			&testRequestHandler{err: errors.New("timeout")},
+		)
+
+		res, err := c.SendTxFanOut(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.ErrorContains(t, err, "a: rejected")
+		require.ErrorContains(t, err, "b: timeout")
+		require.Len(t, res.Results(), 2)
+	})
+
+	t.Run("unconnected_endpoints", func(t *testing.T) {
+		// only the first endpoint is connected by default
+		config, servers := testStandInEndpoints(t, wsGatewayUrl, wsCloudApiUrl, grpcGatewayUrl)
+
+		c, err := NewClient(context.Background(), config)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		res, err := c.SendTxFanOut(context.Background(), &SendTxParams{Transaction: testTxBytes(t)})
+		require.NoError(t, err)
+
+		results := res.Wait()
+		require.Len(t, results, len(servers))
+		for _, result := range results {
+			require.NoError(t, result.Err, result.Endpoint)
+		}
+
+		for i, s := range servers {
+			var received bool
+			for _, req := range s.Requests() {
+				received = received || req.Method == jsonrpc.RPCTx
+			}
+			require.Truef(t, received, "endpoint %d didn't receive the transaction", i)
+		}
+	})
+}
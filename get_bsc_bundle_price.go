@@ -7,6 +7,6 @@ import (
 
 // GetBscBundlePrice gets the BSC bundle price that corresponds to your subscription tier.
 // The response has keys '1', '2', and 'higher', corresponding to the number of transactions in the bundle.
-func (c *Client) GetBscBundlePrice(ctx context.Context) (*json.RawMessage, error) {
-	return c.handler.Request(ctx, RPCBSCGetBundlePrice, nil)
+func (c *Client) GetBscBundlePrice(ctx context.Context, opts ...CallOption) (*json.RawMessage, error) {
+	return c.request(ctx, c.handler, RPCBSCGetBundlePrice, nil, opts)
 }
@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
@@ -22,22 +26,31 @@ import (
 )
 
 type grpcHandler struct {
-	hst           handlerSourceType
-	config        *Config
-	conn          *grpc.ClientConn
-	client        pb.GatewayClient
-	md            metadata.MD
-	stop          chan struct{}
-	wg            *sync.WaitGroup
-	subscriptions map[types.FeedType]grpcSubscription
-	lock          *sync.Mutex
+	hst    handlerSourceType
+	url    string
+	config *Config
+	// logger carries the endpoint and the handler type
+	logger *slog.Logger
+	conn   *grpc.ClientConn
+	client pb.GatewayClient
+	md     metadata.MD
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+	// subscriptions is keyed by the subscription ID assigned by the SDK
+	subscriptions map[string]grpcSubscription
+	// closed is set by Close, so that a stream opened meanwhile isn't registered
+	closed bool
+	// lock guards subscriptions and closed only, as the gRPC client is safe for concurrent use
+	lock *sync.Mutex
 }
 
 type grpcSubscription struct {
-	callback CallbackFunc[any]
-	subReq   any
-	cancel   context.CancelFunc
-	wait     chan struct{}
+	feed       types.FeedType
+	dispatcher *dispatcher
+	subReq     any
+	cancel     context.CancelFunc
+	wait       chan struct{}
 }
 
 // Type returns the handler type
@@ -45,120 +58,269 @@ func (h *grpcHandler) Type() handlerSourceType {
 	return h.hst
 }
 
-// Subscribe subscribes to a feed
-func (h *grpcHandler) Subscribe(ctx context.Context, feed types.FeedType, req any, callback CallbackFunc[any]) error {
-	h.lock.Lock()
-	defer h.lock.Unlock()
+// Healthy reports whether the gRPC connection is not failing
+func (h *grpcHandler) Healthy() bool {
+	state := h.conn.GetState()
+	if state == connectivity.Idle {
+		// an idle connection is never checked, so make it connect
+		h.conn.Connect()
+	}
+
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
+// State returns the state of the gRPC connection
+func (h *grpcHandler) State() State {
+	switch h.conn.GetState() {
+	case connectivity.Idle, connectivity.Ready:
+		// an idle connection connects on the next call
+		return StateConnected
+	case connectivity.Connecting:
+		return StateConnecting
+	case connectivity.TransientFailure:
+		return StateDisconnected
+	default:
+		return StateClosed
+	}
+}
+
+// watchState emits the lifecycle events of the gRPC connection until it is closed.
+// The connection is reconnected by gRPC itself, so a reconnect attempt is each
+// transition to connecting after the connection was lost. A connection which goes
+// idle isn't lost, gRPC closes it for inactivity and connects again on the next call.
+func (h *grpcHandler) watchState(ctx context.Context) {
+	defer h.wg.Done()
+
+	state := h.conn.GetState()
+	var connected, lost bool
+	var attempt int
+	var disconnectedAt time.Time
+	for h.conn.WaitForStateChange(ctx, state) {
+		previous := state
+		state = h.conn.GetState()
+
+		if state == connectivity.Shutdown {
+			return
+		}
 
-	_, ok := h.subscriptions[feed]
+		// the disconnect is reported before the reconnect attempt which follows it
+		if connected && !lost && (state == connectivity.TransientFailure || (previous == connectivity.Ready && state == connectivity.Connecting)) {
+			lost = true
+			disconnectedAt = time.Now()
+			h.config.emit(Event{Type: EventDisconnected, Endpoint: h.url, Err: fmt.Errorf("gRPC connection is %s", state)})
+		}
+
+		switch state {
+		case connectivity.Ready:
+			if connected && !lost {
+				// back from idle
+				break
+			}
+			if lost {
+				h.config.metrics().Disconnected(h.url, time.Since(disconnectedAt))
+			}
+			connected = true
+			lost = false
+			attempt = 0
+			h.config.emit(Event{Type: EventConnected, Endpoint: h.url})
+		case connectivity.Connecting:
+			if lost {
+				attempt++
+				h.config.emit(Event{Type: EventReconnecting, Endpoint: h.url, Attempt: attempt})
+				h.config.metrics().Reconnect(h.url)
+			}
+		}
+	}
+}
+
+// Subscribe subscribes to a feed with the given subscription ID
+func (h *grpcHandler) Subscribe(ctx context.Context, id string, feed types.FeedType, req any, callback CallbackFunc[any]) error {
+	h.lock.Lock()
+	_, ok := h.subscriptions[id]
+	h.lock.Unlock()
 	if ok {
-		return fmt.Errorf("feed %v already subscribed", feed)
+		return fmt.Errorf("subscription %s to %v already exists", id, feed)
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, h.md)
+	trace.SpanFromContext(ctx).SetAttributes(attrEndpoint.String(h.url), attrHandler.String(h.hst.String()))
+	ctx = h.outgoingContext(ctx)
 
 	subCtx, cancel := context.WithCancel(ctx)
+
+	stream, err := h.openStream(subCtx, feed, req)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	// the stream was opened without the lock, so the handler may have changed meanwhile
+	if h.closed {
+		cancel()
+		return fmt.Errorf("failed to subscribe to %v: the handler is closed", feed)
+	}
+	if _, ok := h.subscriptions[id]; ok {
+		cancel()
+		return fmt.Errorf("subscription %s to %v already exists", id, feed)
+	}
+
+	h.sub(subCtx, cancel, id, feed, stream, req, callback)
+
+	return nil
+}
+
+// openStream opens the stream of the feed and returns its receive function
+func (h *grpcHandler) openStream(ctx context.Context, feed types.FeedType, req any) (func() (any, error), error) {
 	var wrapStream func() (any, error)
-	var err error
 
 	switch feed {
 	case types.NewTxsFeed:
 		params := req.(*NewTxParams)
-		var stream pb.Gateway_NewTxsClient
-		stream, err = h.client.NewTxs(subCtx, &pb.TxsRequest{Filters: params.Filters, Includes: params.Include})
+		stream, err := h.client.NewTxs(ctx, &pb.TxsRequest{Filters: params.Filters, Includes: params.Include})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("failed to subscribe to %s: %w", feed, err)
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", feed, err)
 		}
 		wrapStream = func() (any, error) {
 			return stream.Recv()
 		}
 	case types.PendingTxsFeed:
 		params := req.(*PendingTxParams)
-		var stream pb.Gateway_PendingTxsClient
-		stream, err = h.client.PendingTxs(subCtx, &pb.TxsRequest{Filters: params.Filters, Includes: params.Include})
+		stream, err := h.client.PendingTxs(ctx, &pb.TxsRequest{Filters: params.Filters, Includes: params.Include})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("failed to subscribe to %s: %w", feed, err)
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", feed, err)
 		}
 		wrapStream = func() (any, error) {
 			return stream.Recv()
 		}
 	case types.NewBlocksFeed:
 		params := req.(*NewBlockParams)
-		var stream pb.Gateway_NewBlocksClient
-		stream, err = h.client.NewBlocks(subCtx, &pb.BlocksRequest{Includes: params.Include})
+		stream, err := h.client.NewBlocks(ctx, &pb.BlocksRequest{Includes: params.Include})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("failed to subscribe to %s: %w", feed, err)
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", feed, err)
 		}
 		wrapStream = func() (any, error) {
 			return stream.Recv()
 		}
 	case types.BDNBlocksFeed:
 		params := req.(*BdnBlockParams)
-		var stream pb.Gateway_BdnBlocksClient
-		stream, err = h.client.BdnBlocks(subCtx, &pb.BlocksRequest{Includes: params.Include})
+		stream, err := h.client.BdnBlocks(ctx, &pb.BlocksRequest{Includes: params.Include})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("failed to subscribe to %s: %w", feed, err)
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", feed, err)
 		}
 		wrapStream = func() (any, error) {
 			return stream.Recv()
 		}
 	case types.TxReceiptsFeed:
 		params := req.(*TxReceiptParams)
-		var stream pb.Gateway_TxReceiptsClient
-		stream, err = h.client.TxReceipts(subCtx, &pb.TxReceiptsRequest{Includes: params.Include})
+		stream, err := h.client.TxReceipts(ctx, &pb.TxReceiptsRequest{Includes: params.Include})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("failed to subscribe to %s: %w", feed, err)
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", feed, err)
 		}
 		wrapStream = func() (any, error) {
 			return stream.Recv()
 		}
 	default:
-		cancel()
-		return fmt.Errorf("%s feed type is not yet supported", feed)
+		return nil, fmt.Errorf("%s feed type is not yet supported", feed)
 	}
 
-	h.sub(subCtx, cancel, feed, wrapStream, req, callback)
+	return wrapStream, nil
+}
 
-	return nil
+// newStreamBackOff returns the backoff of the attempts to reopen the stream of a subscription
+func newStreamBackOff() *backoff.ExponentialBackOff {
+	backOff := backoff.NewExponentialBackOff()
+	backOff.InitialInterval = reconnectInitialInterval
+	// keep trying for as long as the subscription is active, like the WS handler does
+	backOff.MaxElapsedTime = 0
+
+	return backOff
+}
+
+// reopenStream opens the stream of the feed again after each backoff interval until it succeeds, fails
+// for good or ctx is canceled. The backoff and the attempt count carry over from the previous reopens of the
+// stream, so a stream which fails right after it is opened is not reopened in a tight loop.
+// Each attempt is reported with EventReconnecting and the success with EventResubscribed.
+func (h *grpcHandler) reopenStream(ctx context.Context, id string, feed types.FeedType, req any, backOff backoff.BackOff, attempt *int) (func() (any, error), error) {
+	for {
+		timer := time.NewTimer(backOff.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		*attempt++
+		h.config.emit(Event{Type: EventReconnecting, Endpoint: h.url, Attempt: *attempt, Feed: feed, Subscription: id})
+		h.config.metrics().Reconnect(h.url)
+
+		stream, err := h.openStream(ctx, feed, req)
+		if err != nil {
+			h.logger.Error("failed to reopen stream",
+				slog.String(logKeyFeed, string(feed)), slog.String(logKeySubscription, id), slog.Int(logKeyAttempt, *attempt), slog.Any(logKeyError, err))
+			if permanentStreamError(err) {
+				return nil, err
+			}
+			continue
+		}
+
+		h.config.emit(Event{Type: EventResubscribed, Endpoint: h.url, Feed: feed, Subscription: id})
+
+		return stream, nil
+	}
+}
+
+// permanentStreamError reports whether the stream failed because the gateway rejects it, so opening it again
+// would fail the same way
+func permanentStreamError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied, codes.Unimplemented, codes.InvalidArgument:
+		return true
+	default:
+		return false
+	}
 }
 
 // Request sends a gRPC request
 func (h *grpcHandler) Request(ctx context.Context, method jsonrpc.RPCRequestType, params any) (*json.RawMessage, error) {
-	h.lock.Lock()
-	defer h.lock.Unlock()
-
-	ctx = metadata.NewOutgoingContext(ctx, h.md)
+	trace.SpanFromContext(ctx).SetAttributes(attrEndpoint.String(h.url), attrHandler.String(h.hst.String()))
+	ctx = h.outgoingContext(ctx)
 
-	var response map[string]interface{}
+	var response any
+	var err error
 
+	start := time.Now()
 	switch method {
 	case jsonrpc.RPCTx:
-		sendTxParams, ok := params.(*SendTxParams)
-		if !ok {
-			return nil, fmt.Errorf("failed to cast params: expected %T, got %T", &SendTxParams{}, params)
-		}
-
-		reply, err := h.client.BlxrTx(ctx, &pb.BlxrTxRequest{
-			Transaction:     sendTxParams.Transaction,
-			NonceMonitoring: sendTxParams.NonceMonitoring,
-			NextValidator:   sendTxParams.NextValidator,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to send tx: %w", err)
-		}
-		response = map[string]interface{}{"tx_hash": reply.TxHash}
+		response, err = h.sendTx(ctx, params)
+	case jsonrpc.RPCBatchTx:
+		response, err = h.sendTxBatch(ctx, params)
+	case jsonrpc.RPCBundleSubmission:
+		response, err = h.submitBundle(ctx, params)
+	case jsonrpc.RPCPrivateTx, RPCBSCPrivateTx, RPCPolygonPrivateTx, RPCBSCGetBundlePrice, jsonrpc.RPCStopMonitoringTx, jsonrpc.RPCQuotaUsage:
+		// the gateway gRPC service has no counterpart of these cloud API methods
+		return nil, fmt.Errorf("%s request is not supported over gRPC, use a WebSocket endpoint", method)
 	default:
 		return nil, fmt.Errorf("%s grpc request is not yet supported", method)
 	}
+	h.config.metrics().RequestDuration(h.url, string(method), time.Since(start), err)
+
+	if status.Code(err) == codes.Unavailable {
+		// the connection was lost or couldn't be established
+		return nil, &ConnectionLostError{Endpoint: h.url, Err: err}
+	}
+	if status.Code(err) == codes.ResourceExhausted {
+		return nil, fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	responseJSON, err := json.Marshal(response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal tx hash: %w", err)
+		return nil, fmt.Errorf("failed to marshal %s response: %w", method, err)
 	}
 
 	responseRawMessage := json.RawMessage(responseJSON)
@@ -166,45 +328,246 @@ func (h *grpcHandler) Request(ctx context.Context, method jsonrpc.RPCRequestType
 	return &responseRawMessage, nil
 }
 
-// UnsubscribeRetry unsubscribes from a feed.
-func (h *grpcHandler) UnsubscribeRetry(f types.FeedType) error {
+// sendTx sends a single transaction with BlxrTx
+func (h *grpcHandler) sendTx(ctx context.Context, params any) (*SendTxReply, error) {
+	sendTxParams, ok := params.(*SendTxParams)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast params: expected %T, got %T", &SendTxParams{}, params)
+	}
+
+	reply, err := h.client.BlxrTx(ctx, &pb.BlxrTxRequest{
+		Transaction:     sendTxParams.Transaction,
+		NonceMonitoring: sendTxParams.NonceMonitoring,
+		NextValidator:   sendTxParams.NextValidator,
+		ValidatorsOnly:  sendTxParams.ValidatorsOnly,
+		Fallback:        int32(sendTxParams.Fallback),
+		NodeValidation:  sendTxParams.NodeValidation,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send tx: %w", err)
+	}
+
+	return &SendTxReply{TxHash: reply.TxHash}, nil
+}
+
+// sendTxBatch sends several transactions with BlxrBatchTX
+func (h *grpcHandler) sendTxBatch(ctx context.Context, params any) (*SendTxBatchReply, error) {
+	batchParams, ok := params.(*SendTxBatchParams)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast params: expected %T, got %T", &SendTxBatchParams{}, params)
+	}
+
+	// the gateway sends the transactions to the network it runs on, which is expected to be the one of the client
+	if batchParams.BlockchainNetwork != "" && batchParams.BlockchainNetwork != h.config.BlockchainNetwork {
+		return nil, fmt.Errorf("sending a batch to %s is not supported over gRPC, the gateway is on %s", batchParams.BlockchainNetwork, h.config.BlockchainNetwork)
+	}
+
+	response := &SendTxBatchReply{}
+
+	// indexes maps the index of each transaction sent to the gateway to its index in the batch
+	indexes := make([]int, 0, len(batchParams.Transactions))
+	txsAndSenders := make([]*pb.TxAndSender, 0, len(batchParams.Transactions))
+	for i, rawTx := range batchParams.Transactions {
+		// the gateway expects the sender of each transaction along with it
+		sender, err := txSender(rawTx)
+		if err != nil {
+			response.TxErrors = append(response.TxErrors, BatchTxError{Index: i, Error: fmt.Sprintf("failed to get the sender: %s", err)})
+			continue
+		}
+
+		indexes = append(indexes, i)
+		txsAndSenders = append(txsAndSenders, &pb.TxAndSender{Transaction: rawTx, Sender: sender.Bytes()})
+	}
+
+	if len(txsAndSenders) == 0 {
+		return response, nil
+	}
+
+	reply, err := h.client.BlxrBatchTX(ctx, &pb.BlxrBatchTXRequest{
+		TransactionsAndSenders: txsAndSenders,
+		NonceMonitoring:        batchParams.NonceMonitoring,
+		NextValidator:          batchParams.NextValidator,
+		ValidatorsOnly:         batchParams.ValidatorsOnly,
+		Fallback:               int32(batchParams.Fallback),
+		SendingTime:            time.Now().UnixNano(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send tx batch: %w", err)
+	}
+
+	batchIndex := func(idx int32) int {
+		if idx < 0 || int(idx) >= len(indexes) {
+			return -1
+		}
+		return indexes[idx]
+	}
+
+	for _, txHash := range reply.TxHashes {
+		response.TxHashes = append(response.TxHashes, BatchTxHash{Index: batchIndex(txHash.Idx), TxHash: txHash.TxHash})
+	}
+	for _, txErr := range reply.TxErrors {
+		response.TxErrors = append(response.TxErrors, BatchTxError{Index: batchIndex(txErr.Idx), Error: txErr.Error})
+	}
+
+	return response, nil
+}
+
+// submitBundle submits an ETH or BSC bundle with BlxrSubmitBundle
+func (h *grpcHandler) submitBundle(ctx context.Context, params any) (*SendBundleReply, error) {
+	var req *pb.BlxrSubmitBundleRequest
+
+	switch p := params.(type) {
+	case *SendEthBundleParams:
+		if p.BackRunMe || p.BackRunMeRewardAddress != "" {
+			return nil, fmt.Errorf("BackRunMe is not supported over gRPC")
+		}
+
+		req = &pb.BlxrSubmitBundleRequest{
+			Transactions:    p.Transactions,
+			BlockNumber:     p.BlockNumber,
+			MinTimestamp:    int64(p.MinTimestamp),
+			MaxTimestamp:    int64(p.MaxTimestamp),
+			RevertingHashes: p.RevertingHashes,
+			Uuid:            p.Uuid,
+			MevBuilders:     p.MevBuilders,
+		}
+	case *sendBscBundleParams:
+		req = &pb.BlxrSubmitBundleRequest{
+			Transactions:            p.Transactions,
+			BlockNumber:             p.BlockNumber,
+			MinTimestamp:            int64(p.MinTimestamp),
+			MaxTimestamp:            int64(p.MaxTimestamp),
+			RevertingHashes:         p.RevertingHashes,
+			Uuid:                    p.UUID,
+			AvoidMixedBundles:       p.AvoidMixedBundles,
+			PriorityFeeRefund:       p.PriorityFeeRefund,
+			IncomingRefundRecipient: p.IncomingRefundRecipient,
+			BlocksCount:             int32(p.BlocksCount),
+			DroppingHashes:          p.DroppingTxHashes,
+			MevBuilders:             p.MevBuilders,
+			EndOfBlock:              p.EndOfBlock,
+		}
+	default:
+		return nil, fmt.Errorf("failed to cast params: expected %T or %T, got %T", &SendEthBundleParams{}, &SendBscBundleParams{}, params)
+	}
+
+	reply, err := h.client.BlxrSubmitBundle(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit bundle: %w", err)
+	}
+
+	return &SendBundleReply{BundleHash: reply.BundleHash}, nil
+}
+
+// Unsubscribe ends the subscription with the given ID
+func (h *grpcHandler) Unsubscribe(id string) error {
 	h.lock.Lock()
-	defer h.lock.Unlock()
+	sub, ok := h.subscriptions[id]
+	delete(h.subscriptions, id)
+	h.lock.Unlock()
 
-	sub, ok := h.subscriptions[f]
 	if !ok {
+		return fmt.Errorf("subscription %s not found", id)
+	}
+
+	h.unsubscribe(sub)
+
+	return nil
+}
+
+// UnsubscribeRetry ends all subscriptions to a feed
+func (h *grpcHandler) UnsubscribeRetry(f types.FeedType) error {
+	h.lock.Lock()
+	var subs []grpcSubscription
+	for id, sub := range h.subscriptions {
+		if sub.feed == f {
+			subs = append(subs, sub)
+			delete(h.subscriptions, id)
+		}
+	}
+	h.lock.Unlock()
+
+	if len(subs) == 0 {
 		return fmt.Errorf("feed %v not subscribed", f)
 	}
 
+	for _, sub := range subs {
+		h.unsubscribe(sub)
+	}
+
+	return nil
+}
+
+// unsubscribe cancels the stream of a subscription which was removed from the subscriptions, and waits for it to end
+func (h *grpcHandler) unsubscribe(sub grpcSubscription) {
 	sub.cancel()
 
 	select {
 	case <-sub.wait:
-	case <-time.After(5 * time.Second):
+	case <-time.After(h.config.UnsubscribeTimeout):
 	}
 
-	delete(h.subscriptions, f)
-
-	return nil
+	sub.dispatcher.close()
 }
 
 // Close closes the gRPC connection.
 func (h *grpcHandler) Close() error {
+	h.cancel()
+
+	// cancel the streams first so that they don't try to reopen on the closed connection
+	h.lock.Lock()
+	h.closed = true
+	for _, sub := range h.subscriptions {
+		sub.cancel()
+	}
+	h.lock.Unlock()
+
 	err := h.conn.Close()
 
 	h.wg.Wait()
 
+	h.config.emit(Event{Type: EventClosed, Endpoint: h.url})
+
+	h.lock.Lock()
+	for _, sub := range h.subscriptions {
+		sub.dispatcher.close()
+	}
+	h.lock.Unlock()
+
 	return err
 }
 
-func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f types.FeedType, stream func() (any, error), req any, callback CallbackFunc[any]) {
+// DispatchStats returns the dispatch queue stats of all subscriptions
+func (h *grpcHandler) DispatchStats() []DispatchStats {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	stats := make([]DispatchStats, 0, len(h.subscriptions))
+	for _, sub := range h.subscriptions {
+		stats = append(stats, sub.dispatcher.stats())
+	}
+
+	return stats
+}
+
+// sub registers the subscription and passes the messages of its stream to the callback.
+// It must be called with the lock held.
+func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, id string, f types.FeedType, stream func() (any, error), req any, callback CallbackFunc[any]) {
 	wait := make(chan struct{})
 
-	h.subscriptions[f] = grpcSubscription{
-		callback: callback,
-		subReq:   req,
-		cancel:   cancel,
-		wait:     wait,
+	d := newDispatcher(h.config, id, f, callback, func() {
+		if err := h.Unsubscribe(id); err != nil {
+			h.logger.Error("failed to unsubscribe from overflowed feed",
+				slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+		}
+	})
+
+	h.subscriptions[id] = grpcSubscription{
+		feed:       f,
+		dispatcher: d,
+		subReq:     req,
+		cancel:     cancel,
+		wait:       wait,
 	}
 
 	h.wg.Add(1)
@@ -212,20 +575,57 @@ func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f type
 		defer h.wg.Done() // global wait group
 		defer close(wait) // local signal channel for the subscription
 
+		// the backoff and the attempts are reset only once the stream delivers again
+		backOff := newStreamBackOff()
+		var attempt int
+
 		for {
 			rawResult, err := stream()
 			if err != nil {
+				if ctx.Err() != nil {
+					// unsubscribed or closed
+					break
+				}
+
+				// gRPC reports a message which fails to unmarshal as Internal
+				if status.Code(err) == codes.Internal {
+					h.config.metrics().DecodeError(h.url, string(f))
+				}
+
 				rpcErr, ok := status.FromError(err)
-				if (ok && rpcErr.Code() == codes.Canceled) || errors.Is(err, io.EOF) {
+				if !*h.config.Reconnect || permanentStreamError(err) {
+					if (ok && rpcErr.Code() == codes.Canceled) || errors.Is(err, io.EOF) {
+						break
+					}
+
+					h.logger.Error("failed to receive from stream",
+						slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+					d.dispatch(ctx, err, nil)
 					break
 				}
 
-				h.config.Logger.Errorf("failed to receive response from %s stream: %v", f, err)
+				// the stream is dead, e.g. because the gateway restarted, so open it again
+				h.logger.Error("failed to receive from stream, reopening",
+					slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+
+				stream, err = h.reopenStream(ctx, id, f, req, backOff, &attempt)
+				if err != nil {
+					if ctx.Err() == nil {
+						d.dispatch(ctx, err, nil)
+					}
+					break
+				}
 
-				callback(ctx, err, nil)
 				continue
 			}
 
+			if attempt > 0 {
+				backOff.Reset()
+				attempt = 0
+			}
+
+			h.config.metrics().MessageReceived(h.url, string(f))
+
 			var result any
 
 			switch f {
@@ -240,7 +640,7 @@ func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f type
 						Time:        strconv.FormatInt(resp.Tx[i].Time, 10),
 						RawTx:       string(resp.Tx[i].RawTx),
 					}
-					callback(ctx, nil, result)
+					d.dispatch(ctx, nil, result)
 				}
 				continue
 			case types.NewBlocksFeed, types.BDNBlocksFeed:
@@ -270,7 +670,7 @@ func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f type
 				var header *Header
 				if resp.Header != nil {
 					header = &Header{
-						ParentHash:       resp.Header.WithdrawalsRoot,
+						ParentHash:       resp.Header.ParentHash,
 						Sha3Uncles:       resp.Header.Sha3Uncles,
 						Miner:            resp.Header.Miner,
 						StateRoot:        resp.Header.StateRoot,
@@ -291,17 +691,17 @@ func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f type
 					if resp.Header.BaseFeePerGas != "" {
 						baseFee, err := strconv.Atoi(resp.Header.BaseFeePerGas)
 						if err != nil {
-							callback(ctx, err, nil)
+							d.dispatch(ctx, err, nil)
 							continue
 						}
 						header.BaseFeePerGas = &baseFee
 					}
 					if resp.Header.WithdrawalsRoot != "" {
-						withdrawalsRoot := common.BytesToHash([]byte(resp.Header.WithdrawalsRoot))
+						withdrawalsRoot := common.HexToHash(resp.Header.WithdrawalsRoot)
 						header.WithdrawalsRoot = &withdrawalsRoot
 					}
 					if resp.Header.ParentBeaconRoot != "" {
-						parentBeaconRoot := common.BytesToHash([]byte(resp.Header.ParentBeaconRoot))
+						parentBeaconRoot := common.HexToHash(resp.Header.ParentBeaconRoot)
 						header.ParentBeaconRoot = &parentBeaconRoot
 					}
 				}
@@ -365,7 +765,7 @@ func (h *grpcHandler) sub(ctx context.Context, cancel context.CancelFunc, f type
 				}
 			}
 
-			callback(ctx, nil, result)
+			d.dispatch(ctx, nil, result)
 		}
 	}()
 }
@@ -0,0 +1,396 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testGatewayServer is a gRPC gateway whose first new transactions stream fails after one transaction
+type testGatewayServer struct {
+	pb.UnimplementedGatewayServer
+	streams atomic.Int32
+}
+
+func (s *testGatewayServer) NewTxs(_ *pb.TxsRequest, stream pb.Gateway_NewTxsServer) error {
+	n := s.streams.Add(1)
+
+	err := stream.Send(&pb.TxsReply{Tx: []*pb.Tx{{RawTx: []byte{byte(n)}}}})
+	if err != nil {
+		return err
+	}
+
+	if n == 1 {
+		return status.Error(codes.Unavailable, "gateway is restarting")
+	}
+
+	<-stream.Context().Done()
+	return nil
+}
+
+// testFailingGatewayServer is a gRPC gateway whose new transactions streams fail with the code as soon as they are opened
+type testFailingGatewayServer struct {
+	testGatewayServer
+	code codes.Code
+}
+
+func (s *testFailingGatewayServer) NewTxs(*pb.TxsRequest, pb.Gateway_NewTxsServer) error {
+	s.streams.Add(1)
+
+	return status.Error(s.code, "stream refused")
+}
+
+func (s *testGatewayServer) BlxrTx(_ context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
+	return &pb.BlxrTxReply{TxHash: "0x" + req.Transaction}, nil
+}
+
+func (s *testGatewayServer) BlxrBatchTX(_ context.Context, req *pb.BlxrBatchTXRequest) (*pb.BlxrBatchTXReply, error) {
+	reply := &pb.BlxrBatchTXReply{}
+	for i, tx := range req.TransactionsAndSenders {
+		// only transactions with a sender are accepted
+		if len(tx.Sender) == 0 {
+			reply.TxErrors = append(reply.TxErrors, &pb.ErrorIndex{Idx: int32(i), Error: "no sender"})
+			continue
+		}
+		reply.TxHashes = append(reply.TxHashes, &pb.TxIndex{Idx: int32(i), TxHash: common.BytesToAddress(tx.Sender).Hex()})
+	}
+
+	return reply, nil
+}
+
+func (s *testGatewayServer) BlxrSubmitBundle(_ context.Context, req *pb.BlxrSubmitBundleRequest) (*pb.BlxrSubmitBundleReply, error) {
+	if req.BlockNumber == "" {
+		return nil, status.Error(codes.InvalidArgument, "block number is missing")
+	}
+
+	return &pb.BlxrSubmitBundleReply{BundleHash: req.Uuid}, nil
+}
+
+func testGRPCGateway(t *testing.T, srv pb.GatewayServer) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterGatewayServer(server, srv)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return "grpc://" + listener.Addr().String()
+}
+
+func TestGRPCStreamReconnect(t *testing.T) {
+	t.Run("reconnect", func(t *testing.T) {
+		srv := &testGatewayServer{}
+		events := make(chan Event, 100)
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, srv),
+			AuthHeader:     "auth",
+			OnEvent:        func(e Event) { events <- e },
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		txs := make(chan *NewTxNotification, 10)
+		err = c.OnNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+			require.NoError(t, err)
+			txs <- result
+		})
+		require.NoError(t, err)
+
+		for i := 1; i <= 2; i++ {
+			select {
+			case tx := <-txs:
+				require.Equal(t, string([]byte{byte(i)}), tx.RawTx)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timeout waiting for tx %d", i)
+			}
+		}
+		require.Equal(t, int32(2), srv.streams.Load())
+
+		var reconnecting, resubscribed bool
+		for !resubscribed {
+			select {
+			case e := <-events:
+				switch e.Type {
+				case EventReconnecting:
+					if e.Feed == types.NewTxsFeed {
+						reconnecting = true
+						require.Equal(t, 1, e.Attempt)
+					}
+				case EventResubscribed:
+					resubscribed = true
+					require.Equal(t, types.NewTxsFeed, e.Feed)
+					require.NoError(t, e.Err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the resubscribed event")
+			}
+		}
+		require.True(t, reconnecting)
+	})
+
+	t.Run("no_reconnect", func(t *testing.T) {
+		srv := &testGatewayServer{}
+		reconnect := false
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, srv),
+			AuthHeader:     "auth",
+			Reconnect:      &reconnect,
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		errs := make(chan error, 10)
+		err = c.OnNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+			if err != nil {
+				errs <- err
+			}
+		})
+		require.NoError(t, err)
+
+		select {
+		case err := <-errs:
+			require.Equal(t, codes.Unavailable, status.Code(err))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the stream error")
+		}
+
+		// the stream is not reopened, and the error is passed only once
+		time.Sleep(100 * time.Millisecond)
+		require.Equal(t, int32(1), srv.streams.Load())
+		require.Empty(t, errs)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		srv := &testFailingGatewayServer{code: codes.PermissionDenied}
+		events := make(chan Event, 100)
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, srv),
+			AuthHeader:     "auth",
+			OnEvent: func(e Event) {
+				select {
+				case events <- e:
+				default:
+				}
+			},
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		errs := make(chan error, 10)
+		err = c.OnNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+			if err != nil {
+				errs <- err
+			}
+		})
+		require.NoError(t, err)
+
+		select {
+		case err := <-errs:
+			require.Equal(t, codes.PermissionDenied, status.Code(err))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the stream error")
+		}
+
+		// the gateway would reject the stream again, so it is not reopened
+		time.Sleep(300 * time.Millisecond)
+		require.Equal(t, int32(1), srv.streams.Load())
+		require.Empty(t, errs)
+		for len(events) > 0 {
+			require.NotEqual(t, EventReconnecting, (<-events).Type)
+		}
+	})
+
+	t.Run("failing_stream", func(t *testing.T) {
+		srv := &testFailingGatewayServer{code: codes.Unavailable}
+		events := make(chan Event, 1000)
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: testGRPCGateway(t, srv),
+			AuthHeader:     "auth",
+			OnEvent: func(e Event) {
+				select {
+				case events <- e:
+				default:
+				}
+			},
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		err = c.OnNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+		require.NoError(t, err)
+
+		// each stream fails as soon as it is opened, so the attempts are only spaced out by the backoff
+		time.Sleep(time.Second)
+		require.Less(t, srv.streams.Load(), int32(10))
+
+		var attempts []int
+		for len(events) > 0 {
+			if e := <-events; e.Type == EventReconnecting && e.Feed == types.NewTxsFeed {
+				attempts = append(attempts, e.Attempt)
+			}
+		}
+		require.Greater(t, len(attempts), 1)
+		for i, attempt := range attempts {
+			require.Equal(t, i+1, attempt)
+		}
+	})
+}
+
+func TestGRPCRequest(t *testing.T) {
+	c, err := NewClient(context.Background(), &Config{
+		GRPCGatewayURL: testGRPCGateway(t, &testGatewayServer{}),
+		AuthHeader:     "auth",
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	t.Run("tx", func(t *testing.T) {
+		res, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+
+		var reply SendTxReply
+		require.NoError(t, json.Unmarshal(*res, &reply))
+		require.Equal(t, "0xf86b", reply.TxHash)
+	})
+
+	t.Run("batch_tx", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+
+		tx, err := ethtypes.SignTx(ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    1,
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		}), ethtypes.HomesteadSigner{}, key)
+		require.NoError(t, err)
+
+		rawTx, err := tx.MarshalBinary()
+		require.NoError(t, err)
+
+		results, err := c.SendTxBatch(context.Background(), &SendTxBatchParams{
+			Transactions: []string{"zz", hexutil.Encode(rawTx)[2:]},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		// the transaction which can't be decoded is rejected without failing the batch
+		require.False(t, results[0].Accepted)
+		require.Error(t, results[0].Err)
+
+		require.True(t, results[1].Accepted)
+		require.NoError(t, results[1].Err)
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), results[1].TxHash)
+	})
+
+	t.Run("batch_tx_network", func(t *testing.T) {
+		_, err := c.SendTxBatch(context.Background(), &SendTxBatchParams{Transactions: []string{"f86b"}, BlockchainNetwork: "BSC-Mainnet"})
+		require.ErrorContains(t, err, "not supported over gRPC")
+	})
+
+	t.Run("eth_bundle", func(t *testing.T) {
+		res, err := c.SendEthBundle(context.Background(), &SendEthBundleParams{Transactions: []string{"f86b"}, BlockNumber: "0x1", Uuid: "eth"})
+		require.NoError(t, err)
+
+		var reply SendBundleReply
+		require.NoError(t, json.Unmarshal(*res, &reply))
+		require.Equal(t, "eth", reply.BundleHash)
+
+		_, err = c.SendEthBundle(context.Background(), &SendEthBundleParams{Transactions: []string{"f86b"}})
+		require.Equal(t, codes.InvalidArgument, status.Code(errors.Unwrap(err)))
+
+		_, err = c.SendEthBundle(context.Background(), &SendEthBundleParams{Transactions: []string{"f86b"}, BlockNumber: "0x1", BackRunMe: true})
+		require.Error(t, err)
+	})
+
+	t.Run("bsc_bundle", func(t *testing.T) {
+		res, err := c.SendBscBundle(context.Background(), &SendBscBundleParams{Transactions: []string{"f86b"}, BlockNumber: "0x1", UUID: "bsc"})
+		require.NoError(t, err)
+
+		var reply SendBundleReply
+		require.NoError(t, json.Unmarshal(*res, &reply))
+		require.Equal(t, "bsc", reply.BundleHash)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := c.GetBscBundlePrice(context.Background())
+		require.ErrorContains(t, err, "not supported over gRPC")
+	})
+}
+
+// testSlowGatewayServer is a gRPC gateway which holds the transaction "slow" until release is closed
+type testSlowGatewayServer struct {
+	testGatewayServer
+	release chan struct{}
+}
+
+func (s *testSlowGatewayServer) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
+	if req.Transaction == "slow" {
+		<-s.release
+	}
+
+	return s.testGatewayServer.BlxrTx(ctx, req)
+}
+
+func TestGRPCConcurrentRequests(t *testing.T) {
+	srv := &testSlowGatewayServer{release: make(chan struct{})}
+	c, err := NewClient(context.Background(), &Config{
+		GRPCGatewayURL: testGRPCGateway(t, srv),
+		AuthHeader:     "auth",
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "slow"})
+		slow <- err
+	}()
+
+	// a slow call doesn't hold up the others
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = c.SendTx(ctx, &SendTxParams{Transaction: "f86b"})
+	require.NoError(t, err)
+
+	close(srv.release)
+	require.NoError(t, <-slow)
+}
+
+func TestGRPCRequestConnectionLost(t *testing.T) {
+	// nothing listens on the address after the listener is closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	c, err := NewClient(context.Background(), &Config{GRPCGatewayURL: addr, AuthHeader: "auth"})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+	require.ErrorIs(t, err, ErrConnectionLost)
+}
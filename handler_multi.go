@@ -0,0 +1,612 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const (
+	defaultHealthCheckInterval = time.Second
+	defaultFailoverTimeout     = time.Second * 5
+)
+
+// multiHandler serves requests and subscriptions through one of several endpoints.
+// When the active endpoint stays unhealthy for Config.FailoverTimeout, it switches to the
+// next endpoint in the list and moves all subscriptions to it.
+// With Config.RedundantFeeds or Config.ConnectAllEndpoints, it stays connected to all
+// endpoints, and subscribes to the redundant feeds on each of them.
+type multiHandler struct {
+	config    *Config
+	endpoints []Endpoint
+	// handlers holds the connected handler of each endpoint, nil if not connected
+	handlers    []handler
+	activeIndex int
+	// subscriptions is keyed by the subscription ID, which is the same on all endpoints
+	subscriptions map[string]multiSubscription
+	// switchLock is held for writing while the active handler is replaced.
+	// It is never held during requests or subscriptions, so that a slow endpoint blocks only its own callers.
+	switchLock *sync.RWMutex
+	lock       *sync.Mutex
+	stop       chan struct{}
+	// ctx is the context of the handlers connected after the start, canceled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// multiSubscription keeps what is needed to subscribe to the feed on another endpoint
+type multiSubscription struct {
+	feed     types.FeedType
+	params   any
+	callback CallbackFunc[any]
+	// handler is the handler the subscription is made on, unless the feed is redundant
+	handler handler
+	// redundant is set when the feed is subscribed on all endpoints
+	redundant bool
+	// filter merges the notifications of a redundant feed, and wraps the callback for each endpoint
+	filter *raceFilter
+}
+
+func newMultiHandler(ctx context.Context, config *Config) (*multiHandler, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	h := &multiHandler{
+		config:        config,
+		endpoints:     config.Endpoints,
+		handlers:      make([]handler, len(config.Endpoints)),
+		activeIndex:   -1,
+		subscriptions: make(map[string]multiSubscription),
+		switchLock:    &sync.RWMutex{},
+		lock:          &sync.Mutex{},
+		stop:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+		wg:            &sync.WaitGroup{},
+	}
+
+	// connect to the first endpoint which is available, or to all of them if required
+	var errs []error
+	for i, endpoint := range h.endpoints {
+		active, err := newHandler(ctx, endpoint, config)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+			config.Slog.Error("failed to connect to endpoint", slog.String(logKeyEndpoint, endpoint.String()), slog.Any(logKeyError, err))
+			continue
+		}
+
+		h.handlers[i] = active
+		if h.activeIndex == -1 {
+			h.activeIndex = i
+		}
+
+		if !config.connectAll() {
+			break
+		}
+	}
+
+	if h.activeIndex == -1 {
+		cancel()
+		return nil, fmt.Errorf("failed to connect to any endpoint: %w", errors.Join(errs...))
+	}
+
+	h.wg.Add(1)
+	go h.monitor(ctx)
+
+	return h, nil
+}
+
+// Type returns the type of the active handler
+func (h *multiHandler) Type() handlerSourceType {
+	return h.current().Type()
+}
+
+// Healthy reports whether the active handler is healthy
+func (h *multiHandler) Healthy() bool {
+	return h.current().Healthy()
+}
+
+// State returns the state of the active handler
+func (h *multiHandler) State() State {
+	return h.current().State()
+}
+
+// Subscribe subscribes to a feed on the active endpoint, or on all endpoints if the feed is redundant.
+// If a failover switches endpoints during the subscription, the subscription is made again on the new one.
+func (h *multiHandler) Subscribe(ctx context.Context, id string, f types.FeedType, params any, callback CallbackFunc[any]) error {
+	if h.config.RedundantFeeds && redundantFeed(f) {
+		return h.subscribeRedundant(ctx, id, f, params, callback)
+	}
+
+	for {
+		active := h.current()
+		err := active.Subscribe(ctx, id, f, params, callback)
+		if err != nil {
+			if h.current() != active {
+				// the previous endpoint may be closed by the failover
+				continue
+			}
+
+			return err
+		}
+
+		// the subscription is registered only if the failover which moves the subscriptions hasn't taken place yet
+		h.switchLock.RLock()
+		h.lock.Lock()
+		switched := h.handlers[h.activeIndex] != active
+		if !switched {
+			h.subscriptions[id] = multiSubscription{feed: f, params: params, callback: callback, handler: active}
+		}
+		h.lock.Unlock()
+		h.switchLock.RUnlock()
+
+		if !switched {
+			return nil
+		}
+
+		if err := active.Unsubscribe(id); err != nil {
+			h.config.Slog.Debug("failed to unsubscribe from the previous endpoint",
+				slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+		}
+	}
+}
+
+// subscribeRedundant subscribes to a feed on all connected endpoints,
+// and succeeds if at least one of the subscriptions succeeds
+func (h *multiHandler) subscribeRedundant(ctx context.Context, id string, f types.FeedType, params any, callback CallbackFunc[any]) error {
+	filter := newRaceFilter(h.config.RedundantFeedsCacheSize, callback)
+
+	handlers := h.connected()
+
+	var subscribed int
+	var errs []error
+	for _, hh := range handlers {
+		err := hh.handler.Subscribe(ctx, id, f, params, filter.wrap(hh.endpoint))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hh.endpoint, err))
+			continue
+		}
+
+		subscribed++
+	}
+
+	if subscribed == 0 {
+		return fmt.Errorf("failed to subscribe to %s on any endpoint: %w", f, errors.Join(errs...))
+	}
+
+	for _, err := range errs {
+		h.config.Slog.Error("failed to subscribe to redundant feed",
+			slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+	}
+
+	// an endpoint connected by a failover in the meantime didn't get the subscription from the failover,
+	// so it is subscribed here
+	h.switchLock.RLock()
+	h.lock.Lock()
+	h.subscriptions[id] = multiSubscription{feed: f, params: params, callback: callback, redundant: true, filter: filter}
+	var missed []endpointHandler
+	for i, hh := range h.handlers {
+		if hh != nil && !slices.ContainsFunc(handlers, func(e endpointHandler) bool { return e.handler == hh }) {
+			missed = append(missed, endpointHandler{endpoint: h.endpoints[i].String(), handler: hh})
+		}
+	}
+	h.lock.Unlock()
+	h.switchLock.RUnlock()
+
+	for _, hh := range missed {
+		if err := hh.handler.Subscribe(ctx, id, f, params, filter.wrap(hh.endpoint)); err != nil {
+			h.config.Slog.Error("failed to subscribe to redundant feed", slog.String(logKeyEndpoint, hh.endpoint),
+				slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+		}
+	}
+
+	return nil
+}
+
+// Request sends a request to the active endpoint, or to the endpoint preferred with WithEndpoint if it is connected
+func (h *multiHandler) Request(ctx context.Context, method jsonrpc.RPCRequestType, params any) (*json.RawMessage, error) {
+	return h.requestHandler(ctx).Request(ctx, method, params)
+}
+
+// requestHandler returns the handler of the endpoint preferred with WithEndpoint if it is connected and healthy,
+// or the active handler
+func (h *multiHandler) requestHandler(ctx context.Context) handler {
+	h.switchLock.RLock()
+	defer h.switchLock.RUnlock()
+
+	if url := callOptionsFromContext(ctx).endpoint; url != "" {
+		for i, endpoint := range h.endpoints {
+			if endpoint.String() == url && h.handlers[i] != nil && h.handlers[i].Healthy() {
+				return h.handlers[i]
+			}
+		}
+	}
+
+	return h.handlers[h.activeIndex]
+}
+
+// Unsubscribe ends the subscription on the endpoint it is made on, or on all endpoints if the feed is redundant
+func (h *multiHandler) Unsubscribe(id string) error {
+	h.lock.Lock()
+	subscription, ok := h.subscriptions[id]
+	delete(h.subscriptions, id)
+	h.lock.Unlock()
+
+	if !subscription.redundant {
+		if !ok {
+			return h.current().Unsubscribe(id)
+		}
+
+		return subscription.handler.Unsubscribe(id)
+	}
+
+	var errs []error
+	for _, hh := range h.connected() {
+		errs = append(errs, hh.handler.Unsubscribe(id))
+	}
+
+	return errors.Join(errs...)
+}
+
+// UnsubscribeRetry ends all subscriptions to a feed
+func (h *multiHandler) UnsubscribeRetry(f types.FeedType) error {
+	h.lock.Lock()
+	var ids []string
+	for id, subscription := range h.subscriptions {
+		if subscription.feed == f {
+			ids = append(ids, id)
+		}
+	}
+	h.lock.Unlock()
+
+	var errs []error
+	for _, id := range ids {
+		errs = append(errs, h.Unsubscribe(id))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DispatchStats returns the dispatch queue stats of the subscriptions on all connected endpoints
+func (h *multiHandler) DispatchStats() []DispatchStats {
+	var stats []DispatchStats
+	for _, hh := range h.connected() {
+		stats = append(stats, hh.handler.DispatchStats()...)
+	}
+
+	return stats
+}
+
+// Close stops the health checks and closes all handlers
+func (h *multiHandler) Close() error {
+	close(h.stop)
+	h.cancel()
+	h.wg.Wait()
+
+	h.switchLock.RLock()
+	defer h.switchLock.RUnlock()
+
+	var errs []error
+	for _, hh := range h.handlers {
+		if hh == nil {
+			continue
+		}
+
+		errs = append(errs, hh.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// connected returns the handlers of all connected endpoints, the active one first
+func (h *multiHandler) connected() []endpointHandler {
+	h.switchLock.RLock()
+	defer h.switchLock.RUnlock()
+
+	handlers := make([]endpointHandler, 0, len(h.handlers))
+	handlers = append(handlers, endpointHandler{endpoint: h.endpoints[h.activeIndex].String(), handler: h.handlers[h.activeIndex]})
+	for i, hh := range h.handlers {
+		if hh == nil || i == h.activeIndex {
+			continue
+		}
+
+		handlers = append(handlers, endpointHandler{endpoint: h.endpoints[i].String(), handler: hh})
+	}
+
+	return handlers
+}
+
+// all returns the handlers of all endpoints, the active one first. The endpoints which aren't connected
+// are connected first and stay connected. An endpoint which fails to connect has the error instead of a handler.
+func (h *multiHandler) all() []endpointHandler {
+	h.switchLock.RLock()
+	var missing []int
+	for i, hh := range h.handlers {
+		if hh == nil {
+			missing = append(missing, i)
+		}
+	}
+	h.switchLock.RUnlock()
+
+	errs := make([]error, len(h.endpoints))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(missing))
+	for _, i := range missing {
+		go func(i int) {
+			defer wg.Done()
+
+			hh, err := newHandler(h.ctx, h.endpoints[i], h.config)
+			if err != nil {
+				h.config.Slog.Error("failed to connect to endpoint", slog.String(logKeyEndpoint, h.endpoints[i].String()), slog.Any(logKeyError, err))
+				errs[i] = err
+				return
+			}
+
+			h.keep(i, hh)
+		}(i)
+	}
+	wg.Wait()
+
+	handlers := h.connected()
+	for _, i := range missing {
+		if errs[i] != nil {
+			handlers = append(handlers, endpointHandler{endpoint: h.endpoints[i].String(), err: errs[i]})
+		}
+	}
+
+	return handlers
+}
+
+// keep keeps the handler of the endpoint with the given index connected, and subscribes it to the redundant feeds.
+// The handler is closed instead if the endpoint was connected in the meantime or the multiHandler is closed.
+func (h *multiHandler) keep(index int, hh handler) {
+	h.switchLock.Lock()
+	var kept bool
+	var redundant map[string]multiSubscription
+	select {
+	case <-h.stop:
+	default:
+		if h.handlers[index] == nil {
+			h.handlers[index] = hh
+			redundant = h.snapshot(true)
+			kept = true
+		}
+	}
+	h.switchLock.Unlock()
+
+	if !kept {
+		if err := hh.Close(); err != nil {
+			h.config.Slog.Debug("failed to close handler", slog.String(logKeyEndpoint, h.endpoints[index].String()), slog.Any(logKeyError, err))
+		}
+		return
+	}
+
+	h.subscribeRedundantOn(h.ctx, index, hh, redundant)
+}
+
+// current returns the active handler
+func (h *multiHandler) current() handler {
+	h.switchLock.RLock()
+	defer h.switchLock.RUnlock()
+
+	return h.handlers[h.activeIndex]
+}
+
+// monitor checks the health of the active handler and fails over when
+// it stays unhealthy for longer than the failover timeout
+func (h *multiHandler) monitor(ctx context.Context) {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	var unhealthySince time.Time
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if h.current().Healthy() {
+			unhealthySince = time.Time{}
+			continue
+		}
+
+		if unhealthySince.IsZero() {
+			unhealthySince = time.Now()
+		}
+
+		if time.Since(unhealthySince) < h.config.FailoverTimeout {
+			continue
+		}
+
+		h.failover(ctx)
+		unhealthySince = time.Time{}
+	}
+}
+
+// failover switches to the next available endpoint and moves all subscriptions to it.
+// Without redundant feeds, the next endpoint is connected first and the previous one
+// is closed after the switch. If no other endpoint is available, the active handler
+// is kept and continues to reconnect on its own. An endpoint connected for the first
+// time is also subscribed to the redundant feeds.
+func (h *multiHandler) failover(ctx context.Context) {
+	h.switchLock.RLock()
+	activeIndex := h.activeIndex
+	h.switchLock.RUnlock()
+
+	for i := 1; i < len(h.endpoints); i++ {
+		index := (activeIndex + i) % len(h.endpoints)
+		endpoint := h.endpoints[index]
+
+		h.config.Slog.Warn("endpoint is unhealthy, failing over",
+			slog.String(logKeyEndpoint, h.endpoints[activeIndex].String()), slog.String("next_endpoint", endpoint.String()))
+
+		h.switchLock.RLock()
+		next := h.handlers[index]
+		h.switchLock.RUnlock()
+
+		// a handler which connects for the first time has none of the redundant feeds yet
+		fresh := next == nil
+		if fresh {
+			var err error
+			next, err = newHandler(ctx, endpoint, h.config)
+			if err != nil {
+				h.config.Slog.Error("failed to connect to endpoint", slog.String(logKeyEndpoint, endpoint.String()), slog.Any(logKeyError, err))
+				continue
+			}
+		} else if !next.Healthy() {
+			h.config.Slog.Error("endpoint is unhealthy as well", slog.String(logKeyEndpoint, endpoint.String()))
+			continue
+		}
+
+		// the subscriptions made after the switch are made on the next handler,
+		// so those to move are taken together with the switch
+		h.switchLock.Lock()
+		previous := h.handlers[activeIndex]
+		if !h.config.connectAll() {
+			h.handlers[activeIndex] = nil
+		}
+		h.handlers[index] = next
+		h.activeIndex = index
+		moving := h.snapshot(false)
+		var redundant map[string]multiSubscription
+		if fresh {
+			redundant = h.snapshot(true)
+		}
+		h.switchLock.Unlock()
+
+		// the subscriptions are moved after the switch lock is released,
+		// so that requests and new subscriptions go through the next handler meanwhile
+		moved := h.resubscribeAll(ctx, index, next, moving)
+		if fresh {
+			h.subscribeRedundantOn(ctx, index, next, redundant)
+		}
+
+		if h.config.connectAll() {
+			// the previous handler stays connected,
+			// but must not deliver the feeds which were moved
+			for _, id := range moved {
+				if err := previous.Unsubscribe(id); err != nil {
+					h.config.Slog.Debug("failed to unsubscribe moved subscription",
+						slog.String(logKeyEndpoint, h.endpoints[activeIndex].String()), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+				}
+			}
+		} else if err := previous.Close(); err != nil {
+			h.config.Slog.Debug("failed to close handler", slog.String(logKeyEndpoint, h.endpoints[activeIndex].String()), slog.Any(logKeyError, err))
+		}
+
+		return
+	}
+
+	h.config.Slog.Error("no endpoint is available, staying on the unhealthy one", slog.String(logKeyEndpoint, h.endpoints[activeIndex].String()))
+}
+
+// snapshot returns a copy of the redundant subscriptions, or of the others
+func (h *multiHandler) snapshot(redundant bool) map[string]multiSubscription {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	subCopy := make(map[string]multiSubscription, len(h.subscriptions))
+	for id, subscription := range h.subscriptions {
+		if subscription.redundant != redundant {
+			continue
+		}
+		subCopy[id] = subscription
+	}
+
+	return subCopy
+}
+
+// resubscribeAll subscribes to the feeds of the subscriptions on the handler of the endpoint with the given index,
+// and returns the IDs of the subscriptions it moved off the previous handler. A subscription which fails to move
+// is dropped, and the failure is passed to its callback and reported with EventResubscribed.
+func (h *multiHandler) resubscribeAll(ctx context.Context, index int, next handler, subscriptions map[string]multiSubscription) []string {
+	endpoint := h.endpoints[index].String()
+
+	moved := make([]string, 0, len(subscriptions))
+	for id, subscription := range subscriptions {
+		moved = append(moved, id)
+
+		err := next.Subscribe(ctx, id, subscription.feed, subscription.params, subscription.callback)
+
+		h.lock.Lock()
+		_, active := h.subscriptions[id]
+		if active && err != nil {
+			delete(h.subscriptions, id)
+		} else if active {
+			subscription.handler = next
+			h.subscriptions[id] = subscription
+		}
+		h.lock.Unlock()
+
+		if !active {
+			// unsubscribed while it was being moved
+			if err == nil {
+				if err := next.Unsubscribe(id); err != nil {
+					h.config.Slog.Debug("failed to unsubscribe moved subscription",
+						slog.String(logKeyEndpoint, endpoint), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+				}
+			}
+			continue
+		}
+
+		if err != nil {
+			h.config.Slog.Error("failed to resubscribe after failover", slog.String(logKeyEndpoint, endpoint),
+				slog.String(logKeyFeed, string(subscription.feed)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+			subscription.callback(ctx, fmt.Errorf("failed to move the subscription to %s: %w", endpoint, err), nil)
+		}
+
+		h.config.emit(Event{Type: EventResubscribed, Endpoint: endpoint, Feed: subscription.feed, Subscription: id, Err: err})
+	}
+
+	return moved
+}
+
+// subscribeRedundantOn subscribes to the redundant feeds of the subscriptions
+// on the handler of the endpoint with the given index
+func (h *multiHandler) subscribeRedundantOn(ctx context.Context, index int, hh handler, subscriptions map[string]multiSubscription) {
+	endpoint := h.endpoints[index].String()
+
+	for id, subscription := range subscriptions {
+		err := hh.Subscribe(ctx, id, subscription.feed, subscription.params, subscription.filter.wrap(endpoint))
+		if err != nil {
+			h.config.Slog.Error("failed to subscribe to redundant feed",
+				slog.String(logKeyEndpoint, endpoint), slog.String(logKeyFeed, string(subscription.feed)),
+				slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+			continue
+		}
+
+		h.lock.Lock()
+		_, active := h.subscriptions[id]
+		h.lock.Unlock()
+
+		if !active {
+			// unsubscribed while it was being subscribed here
+			if err := hh.Unsubscribe(id); err != nil {
+				h.config.Slog.Debug("failed to unsubscribe redundant feed",
+					slog.String(logKeyEndpoint, endpoint), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+			}
+		}
+	}
+}
+
+// activeHandler returns the handler which currently serves requests
+func activeHandler(h handler) handler {
+	if m, ok := h.(*multiHandler); ok {
+		return m.current()
+	}
+
+	return h
+}
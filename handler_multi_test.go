@@ -0,0 +1,254 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/bxtest"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+func TestFailover(t *testing.T) {
+	reconnect := false
+	config := testEndpointsConfig(t, wsGatewayUrl, wsCloudApiUrl)
+	config.Reconnect = &reconnect
+	config.HealthCheckInterval = 100 * time.Millisecond
+	config.FailoverTimeout = 500 * time.Millisecond
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+
+	receive := make(chan struct{}, 1)
+
+	err = c.OnNewTx(context.Background(), &NewTxParams{}, func(ctx context.Context, err error, result *NewTxNotification) {
+		require.NoError(t, err)
+
+		select {
+		case receive <- struct{}{}:
+		default:
+		}
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-receive:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timeout waiting for new tx")
+	}
+
+	// drop the connection of the active endpoint
+	h := c.handler.(*multiHandler)
+	first := h.current()
+	require.NoError(t, first.(*wsHandler).conn.Close())
+
+	require.Eventually(t, func() bool { return h.current() != first }, 10*time.Second, 100*time.Millisecond)
+	require.Equal(t, handlerSourceTypeCloudAPIWS, c.handler.Type())
+
+	// the subscription is expected to be moved to the new endpoint
+	select {
+	case <-receive:
+	default:
+	}
+
+	select {
+	case <-receive:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timeout waiting for new tx after failover")
+	}
+
+	require.NoError(t, c.UnsubscribeFromNewTxs())
+	require.NoError(t, c.Close())
+}
+
+func TestRedundantFeeds(t *testing.T) {
+	config := testEndpointsConfig(t, wsCloudApiUrl, grpcGatewayUrl)
+	config.RedundantFeeds = true
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+
+	seen := make(map[string]struct{})
+	receive := make(chan struct{})
+	var count int
+	var lock sync.Mutex
+
+	// the endpoints may call the callback concurrently
+	err = c.OnNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+		require.NoError(t, err)
+		require.NotNil(t, result.Race)
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		key := notificationKey(result)
+		_, ok := seen[key]
+		require.Falsef(t, ok, "transaction %s delivered twice", key)
+		seen[key] = struct{}{}
+
+		count++
+		if count == 100 {
+			close(receive)
+		}
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-receive:
+	case <-time.After(30 * time.Second):
+		require.Fail(t, "timeout waiting for new txs")
+	}
+
+	require.NoError(t, c.UnsubscribeFromNewTxs())
+	require.NoError(t, c.Close())
+}
+
+// testStandInEndpoints returns the config of a client of the stand-ins of the URLs, in order, and the stand-ins.
+// The client fails over quickly and doesn't reconnect, so a disconnected stand-in stays unhealthy.
+func testStandInEndpoints(t *testing.T, urls ...testURL) (*Config, []*bxtest.Server) {
+	t.Helper()
+
+	reconnect := false
+	c := &Config{
+		AuthHeader:          standInAuthHeader,
+		Reconnect:           &reconnect,
+		HealthCheckInterval: 20 * time.Millisecond,
+		FailoverTimeout:     100 * time.Millisecond,
+	}
+
+	var servers []*bxtest.Server
+	for _, url := range urls {
+		config, s := testStandIn(t, url)
+		c.Endpoints = append(c.Endpoints, Endpoint{
+			WSCloudAPIURL:   config.WSCloudAPIURL,
+			WSGatewayURL:    config.WSGatewayURL,
+			GRPCCloudAPIURL: config.GRPCCloudAPIURL,
+			GRPCGatewayURL:  config.GRPCGatewayURL,
+		})
+		servers = append(servers, s)
+	}
+
+	return c, servers
+}
+
+func TestFailoverDoesNotBlockRequests(t *testing.T) {
+	config, servers := testStandInEndpoints(t, wsGatewayUrl, wsCloudApiUrl)
+
+	resubscribed := make(chan Event, 1)
+	config.OnEvent = func(event Event) {
+		if event.Type == EventResubscribed {
+			select {
+			case resubscribed <- event:
+			default:
+			}
+		}
+	}
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = c.SubscribeNewTx(ctx, nil, func(context.Context, error, *NewTxNotification) {})
+	require.NoError(t, err)
+	require.NoError(t, servers[0].WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+
+	// the subscription is moved slowly, which must not hold up the requests to the next endpoint
+	servers[1].Delay(jsonrpc.RPCSubscribe, 2*time.Second)
+
+	h := c.handler.(*multiHandler)
+	first := h.current()
+	servers[0].Disconnect()
+	require.Eventually(t, func() bool { return h.current() != first }, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	_, err = c.SendTx(ctx, &SendTxParams{Transaction: testTxBytes(t)})
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+
+	select {
+	case event := <-resubscribed:
+		require.NoError(t, event.Err)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the resubscription")
+	}
+	require.NoError(t, servers[1].WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+}
+
+func TestFailoverResubscribeFailure(t *testing.T) {
+	config, servers := testStandInEndpoints(t, wsGatewayUrl, wsCloudApiUrl)
+
+	resubscribed := make(chan Event, 1)
+	config.OnEvent = func(event Event) {
+		if event.Type == EventResubscribed {
+			select {
+			case resubscribed <- event:
+			default:
+			}
+		}
+	}
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	callbackErrs := make(chan error, 1)
+	sub, err := c.SubscribeNewTx(ctx, nil, func(_ context.Context, err error, _ *NewTxNotification) {
+		if err != nil {
+			select {
+			case callbackErrs <- err:
+			default:
+			}
+		}
+	})
+	require.NoError(t, err)
+	require.NoError(t, servers[0].WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+
+	servers[1].Handle(jsonrpc.RPCSubscribe, func(context.Context, *bxtest.Request) (any, error) {
+		return nil, &bxtest.Error{Code: bxtest.CodeInternalError, Message: "refused"}
+	})
+	servers[0].Disconnect()
+
+	select {
+	case event := <-resubscribed:
+		require.Error(t, event.Err)
+		require.Equal(t, sub.ID(), event.Subscription)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the failed resubscription")
+	}
+
+	select {
+	case err := <-callbackErrs:
+		require.Error(t, err)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the error of the callback")
+	}
+
+	// the subscription is dropped rather than left behind on the previous endpoint
+	h := c.handler.(*multiHandler)
+	h.lock.Lock()
+	require.Empty(t, h.subscriptions)
+	h.lock.Unlock()
+}
+
+func TestNegativeHealthCheckInterval(t *testing.T) {
+	config, _ := testStandInEndpoints(t, wsGatewayUrl, wsCloudApiUrl)
+	config.HealthCheckInterval = -time.Second
+	config.FailoverTimeout = -time.Second
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+
+	require.Equal(t, defaultHealthCheckInterval, config.HealthCheckInterval)
+	require.Equal(t, defaultFailoverTimeout, config.FailoverTimeout)
+}
@@ -5,14 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/sourcegraph/jsonrpc2"
 	"github.com/valyala/fastjson"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	"github.com/bloXroute-Labs/gateway/v2/types"
@@ -26,12 +29,12 @@ const (
 	languageHeaderKey   = "X-BloXroute-Code-Language"
 	authHeaderKey       = "Authorization"
 
-	requestWaitTimeout = time.Minute
+	defaultRequestTimeout = time.Minute
 
-	unsubscribeTimeout         = time.Second * 10
+	defaultUnsubscribeTimeout  = time.Second * 10
 	unsubscribeInitialInterval = time.Millisecond * 100
 
-	reconnectTimeout         = time.Second * 60
+	defaultReconnectTimeout  = time.Second * 60
 	reconnectInitialInterval = time.Millisecond * 100
 
 	closeTimeout = time.Millisecond * 200
@@ -40,19 +43,36 @@ const (
 var (
 	ErrNotConnected = errors.New("WS connection not established")
 	ErrNoResponse   = errors.New("no response")
+	// ErrRequestTimeout is returned when the response to a request doesn't arrive in time
+	ErrRequestTimeout = errors.New("request timed out")
+
+	// errSubscriptionEnded is returned when a subscription is ended before it is resubscribed after a reconnect
+	errSubscriptionEnded = errors.New("subscription ended")
 )
 
 type wsHandler struct {
-	hst             handlerSourceType
-	config          *Config
-	conn            ws.Conn
-	feeds           map[types.FeedType]feed
-	subscriptions   map[string]wsSubscription
-	pendingResponse map[jsonrpc2.ID]chan requestResponse
+	hst    handlerSourceType
+	url    string
+	config *Config
+	// logger carries the endpoint and the handler type
+	logger *slog.Logger
+	conn   ws.Conn
+	// subscriptions is keyed by the subscription ID assigned by the SDK,
+	// which stays the same when the subscription is renewed after a reconnect
+	subscriptions map[string]*wsSubscription
+	// serverIDs maps the subscription IDs assigned by the server to the SDK ones
+	serverIDs       map[string]string
+	pendingResponse map[jsonrpc2.ID]*pendingRequest
 	lock            *sync.Mutex
 	stop            chan struct{}
+	cancel          context.CancelFunc
 	wg              *sync.WaitGroup
 	readErr         chan error
+	state           atomic.Int32
+	// cancelResubscribe supersedes the resubscription started after the previous reconnect,
+	// and resubscribed is closed once that resubscription has returned
+	cancelResubscribe context.CancelFunc
+	resubscribed      chan struct{}
 }
 
 // requestResponse represents a response to either a normal request or
@@ -62,17 +82,33 @@ type requestResponse struct {
 	ID     string
 	Result []byte
 	Error  *RPCError
+	// Err is set when the request failed before its response arrived
+	Err error
 }
 
-// subscription represents a subscription to a feed
-type wsSubscription struct {
-	callback CallbackFunc[any]
-	feed     types.FeedType
-	subReq   *jsonrpc2.Request
+// pendingRequest is a request waiting for its response
+type pendingRequest struct {
+	resChan chan requestResponse
+	req     *jsonrpc2.Request
+	// subscription is set for subscribe requests
+	subscription *wsSubscription
+	// resubscribe is set when the subscription is renewed after a reconnect
+	resubscribe bool
+	// idempotent requests are sent again after a reconnect instead of failing
+	idempotent bool
+	// replay is set while an idempotent request waits to be sent again after a reconnect
+	replay   bool
+	priority Priority
 }
 
-type feed struct {
-	subscriptionID string
+// subscription represents a subscription to a feed
+type wsSubscription struct {
+	id string
+	// serverID is the subscription ID assigned by the server, empty until the subscription is confirmed
+	serverID   string
+	dispatcher *dispatcher
+	feed       types.FeedType
+	subReq     *jsonrpc2.Request
 }
 
 // Type returns the WS handler type
@@ -80,25 +116,75 @@ func (h *wsHandler) Type() handlerSourceType {
 	return h.hst
 }
 
-// Subscribe subscribes to a feed
-func (h *wsHandler) Subscribe(ctx context.Context, f types.FeedType, params any, callback CallbackFunc[any]) error {
+// Healthy reports whether the WS connection is established
+func (h *wsHandler) Healthy() bool {
+	return h.State() == StateConnected
+}
+
+// State returns the state of the WS connection
+func (h *wsHandler) State() State {
+	return State(h.state.Load())
+}
+
+func (h *wsHandler) setState(state State) {
+	h.state.Store(int32(state))
+}
+
+// Subscribe subscribes to a feed with the given subscription ID
+func (h *wsHandler) Subscribe(ctx context.Context, id string, f types.FeedType, params any, callback CallbackFunc[any]) error {
 	raw, err := json.Marshal([]interface{}{f, params})
 	if err != nil {
 		return fmt.Errorf("failed to marshal params: %w", err)
 	}
-	req := &jsonrpc2.Request{
-		ID:     randomID(),
-		Method: string(jsonrpc.RPCSubscribe),
-		Params: (*json.RawMessage)(&raw),
+
+	subscription := &wsSubscription{
+		id:   id,
+		feed: f,
+		subReq: &jsonrpc2.Request{
+			ID:     randomID(),
+			Method: string(jsonrpc.RPCSubscribe),
+			Params: (*json.RawMessage)(&raw),
+		},
+		dispatcher: newDispatcher(h.config, id, f, callback, func() {
+			if err := h.Unsubscribe(id); err != nil {
+				h.logger.Error("failed to unsubscribe from overflowed feed",
+					slog.String(logKeyFeed, string(f)), slog.String(logKeySubscription, id), slog.Any(logKeyError, err))
+			}
+		}),
 	}
 
-	resChan, err := h.subscribe(ctx, f, req)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attrEndpoint.String(h.url),
+		attrHandler.String(h.hst.String()),
+		attrRequestID.String(subscription.subReq.ID.String()),
+	)
+
+	resChan, err := h.subscribe(ctx, subscription, false)
 	if err != nil {
+		subscription.dispatcher.close()
 		return err
 	}
 
-	_, err = h.waitSubscriptionResponse(ctx, resChan, f, req, callback)
-	return err
+	_, err = h.waitSubscriptionResponse(ctx, resChan, subscription, false)
+	if err != nil {
+		subscription.dispatcher.close()
+		return err
+	}
+
+	return nil
+}
+
+// DispatchStats returns the dispatch queue stats of all subscriptions
+func (h *wsHandler) DispatchStats() []DispatchStats {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	stats := make([]DispatchStats, 0, len(h.subscriptions))
+	for _, subscription := range h.subscriptions {
+		stats = append(stats, subscription.dispatcher.stats())
+	}
+
+	return stats
 }
 
 // Request sends a request via WS
@@ -114,17 +200,25 @@ func (h *wsHandler) Request(ctx context.Context, method jsonrpc.RPCRequestType,
 		Params: (*json.RawMessage)(&raw),
 	}
 
-	resChan, err := h.request(ctx, req)
+	start := time.Now()
+	resChan, err := h.request(ctx, req, isIdempotent(ctx, method))
 	if err != nil {
+		h.config.metrics().RequestDuration(h.url, string(method), time.Since(start), err)
 		return nil, err
 	}
 
-	return h.waitRequestResponse(ctx, resChan, req)
+	res, err := h.waitRequestResponse(ctx, resChan, req)
+	h.config.metrics().RequestDuration(h.url, string(method), time.Since(start), err)
+
+	return res, err
 }
 
 // Close stops the read loop, unsubscribes from all feeds and closes the connection
 func (h *wsHandler) Close() error {
 	close(h.stop)
+	h.setState(StateClosed)
+	// interrupt a reconnect which may be in progress
+	h.cancel()
 
 	// unsubscribe from all feeds and close the connection
 	unsubscribeRequest := &jsonrpc2.Request{
@@ -132,11 +226,19 @@ func (h *wsHandler) Close() error {
 		Method: string(jsonrpc.RPCUnsubscribe),
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.UnsubscribeTimeout)
 	defer cancel()
 
 	err := errors.Join(h.conn.WriteJSON(ctx, unsubscribeRequest), h.conn.Close())
 
+	h.failPending(ws.ErrAlreadyClosed, false)
+
+	h.lock.Lock()
+	for _, subscription := range h.subscriptions {
+		subscription.dispatcher.close()
+	}
+	h.lock.Unlock()
+
 	// this is workaround for the fact that the Read blocks until there is a message
 	// and some WS implementations don't support ctx cancellation
 	c := make(chan struct{})
@@ -149,20 +251,81 @@ func (h *wsHandler) Close() error {
 	case <-time.After(closeTimeout):
 	}
 
+	h.config.emit(Event{Type: EventClosed, Endpoint: h.url})
+
 	return err
 }
 
-// UnsubscribeRetry unsubscribes from a feed with retries
-func (h *wsHandler) UnsubscribeRetry(f types.FeedType) error {
+// Unsubscribe ends the subscription with the given ID, with retries
+func (h *wsHandler) Unsubscribe(id string) error {
 	backOff := backoff.NewExponentialBackOff()
-	backOff.MaxElapsedTime = unsubscribeTimeout
+	backOff.MaxElapsedTime = h.config.UnsubscribeTimeout
 	backOff.InitialInterval = unsubscribeInitialInterval
 
 	fn := func() error {
-		return h.unsubscribe(f)
+		return h.unsubscribe(id)
 	}
 
-	return backoff.Retry(fn, backOff)
+	err := backoff.Retry(fn, backOff)
+	if err != nil {
+		// the subscription is dropped even if the server wasn't told, so that it isn't resubscribed after a reconnect
+		h.lock.Lock()
+		if subscription, ok := h.subscriptions[id]; ok {
+			h.dropSubscription(subscription)
+		}
+		h.lock.Unlock()
+	}
+
+	return err
+}
+
+// UnsubscribeRetry ends all subscriptions to a feed with retries
+func (h *wsHandler) UnsubscribeRetry(f types.FeedType) error {
+	var errs []error
+	for _, id := range h.feedSubscriptions(f) {
+		errs = append(errs, h.Unsubscribe(id))
+	}
+
+	return errors.Join(errs...)
+}
+
+// feedSubscriptions returns the IDs of all subscriptions to a feed
+func (h *wsHandler) feedSubscriptions(f types.FeedType) []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var ids []string
+	for id, subscription := range h.subscriptions {
+		if subscription.feed == f {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// feedServerID returns the ID and the server ID of the confirmed subscription to the feed with the given ID,
+// or of the only subscription to the feed if the ID is empty
+func (h *wsHandler) feedServerID(f types.FeedType, id string) (string, string, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var found *wsSubscription
+	for _, subscription := range h.subscriptions {
+		if subscription.feed != f || (id != "" && subscription.id != id) {
+			continue
+		}
+		if found != nil {
+			return "", "", ErrSeveralSubIDs
+		}
+		found = subscription
+	}
+
+	if found == nil || found.serverID == "" {
+		return "", "", ErrNoSubID
+	}
+
+	return found.id, found.serverID, nil
 }
 
 // read starts reading messages via WS from gateway or cloud API and handles them.
@@ -170,6 +333,10 @@ func (h *wsHandler) UnsubscribeRetry(f types.FeedType) error {
 func (h *wsHandler) read(ctx context.Context) {
 	defer h.wg.Done()
 
+	// attempt counts the reconnects since the connection was lost
+	var attempt int
+	var disconnectedAt time.Time
+
 	for {
 		select {
 		case <-h.stop:
@@ -186,20 +353,53 @@ func (h *wsHandler) read(ctx context.Context) {
 				default:
 				}
 
-				if !ws.IsWSClosedError(err) || !*h.config.Reconnect {
-					h.config.Logger.Errorf("failed to read message from WS: %s", err)
+				reconnecting := ws.IsWSClosedError(err) && *h.config.Reconnect
+
+				if attempt == 0 {
+					disconnectedAt = time.Now()
+					h.setState(StateDisconnected)
+					h.config.emit(Event{Type: EventDisconnected, Endpoint: h.url, Err: err})
+
+					// the responses to the requests in flight will never arrive,
+					// except for the idempotent requests which are sent again after the reconnect
+					h.failPending(err, reconnecting)
+				}
+
+				if !reconnecting {
+					h.logger.Error("failed to read message from WS", slog.Any(logKeyError, err))
+					h.failPending(err, false)
 					return
 				}
 
 				// connection is closed, try to reconnect
+				attempt++
+				h.setState(StateReconnecting)
+				h.config.emit(Event{Type: EventReconnecting, Endpoint: h.url, Attempt: attempt})
+				h.config.metrics().Reconnect(h.url)
+
 				err := h.reconnect(ctx)
 				if err != nil {
-					h.config.Logger.Errorf("failed to reconnect to WS: %s", err)
+					h.logger.Error("failed to reconnect to WS", slog.Int(logKeyAttempt, attempt), slog.Any(logKeyError, err))
 					continue
 				}
 
+				select {
+				case <-h.stop:
+					// client was closed while reconnecting
+					_ = h.conn.Close()
+					return
+				default:
+				}
+
+				attempt = 0
+				h.setState(StateConnected)
+				h.config.emit(Event{Type: EventConnected, Endpoint: h.url})
+				h.config.metrics().Disconnected(h.url, time.Since(disconnectedAt))
+
+				h.replayPending(ctx)
+
 				// try to re-subscribe to all feeds
-				go h.resubscribeAll(ctx)
+				h.startResubscribe(ctx)
 
 				continue
 			}
@@ -211,32 +411,37 @@ func (h *wsHandler) read(ctx context.Context) {
 
 			err = h.handleMessage(ctx, message)
 			if err != nil {
-				h.config.Logger.Errorf("failed to handle message: %s", err)
+				h.logger.Error("failed to handle message", slog.Any(logKeyError, err))
 			}
 		}
 	}
 }
 
 func (h *wsHandler) reconnect(ctx context.Context) error {
-	var urlString string
-	if h.config.WSCloudAPIURL != "" {
-		urlString = h.config.WSCloudAPIURL
-	} else {
-		urlString = h.config.WSGatewayURL
+	authHeader, err := h.config.AuthProvider.AuthHeader(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get auth header: %w", err)
 	}
 
 	headers := http.Header{
-		authHeaderKey:       []string{h.config.AuthHeader},
+		authHeaderKey:       []string{authHeader},
 		blockchainHeaderKey: []string{h.config.BlockchainNetwork},
 		sdkVersionHeaderKey: []string{buildVersion},
 		languageHeaderKey:   []string{runtime.Version()},
 	}
 
-	conn, err := h.config.WSConnectFunc(ctx, urlString, headers, h.config.WSDialOptions)
+	dialOpts := h.config.wsDialOptions(h.hst)
+	dialOpts.Logger = h.logger
+
+	conn, err := h.config.WSConnectFunc(ctx, h.url, headers, dialOpts)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WS: %w", err)
 	}
 
+	if h.config.WrapWSConn != nil {
+		conn = h.config.WrapWSConn(h.url, conn)
+	}
+
 	h.conn = conn
 
 	return nil
@@ -253,10 +458,10 @@ func (h *wsHandler) handleMessage(ctx context.Context, message []byte) error {
 	id := jsonrpc2.ID{Str: string(v.GetStringBytes("id")), IsString: true}
 
 	h.lock.Lock()
-	resChan, ok := h.pendingResponse[id]
+	pending, ok := h.pendingResponse[id]
 	h.lock.Unlock()
 	if ok {
-		return h.handlePendingResponse(id, resChan, v)
+		return h.handlePendingResponse(id, pending.resChan, v)
 	}
 
 	method := v.GetStringBytes("method")
@@ -265,7 +470,7 @@ func (h *wsHandler) handleMessage(ctx context.Context, message []byte) error {
 	}
 
 	h.lock.Lock()
-	subscription, ok := h.subscriptions[string(v.GetStringBytes("params", "subscription"))]
+	subscription, ok := h.subscriptions[h.serverIDs[string(v.GetStringBytes("params", "subscription"))]]
 	h.lock.Unlock()
 	if !ok {
 		// subscription not found
@@ -284,19 +489,19 @@ func (h *wsHandler) handleMessage(ctx context.Context, message []byte) error {
 		}
 	case types.BDNBlocksFeed:
 		res = &OnBdnBlockNotification{}
-		err = json.Unmarshal(v.GetObject("params", "result").MarshalTo(nil), &res)
+		err = unmarshalResult(v, res)
 		if err != nil {
 			err = fmt.Errorf("failed to unmarshal bdn block notification: %w", err)
 		}
 	case types.NewBlocksFeed:
 		res = &OnBdnBlockNotification{}
-		err = json.Unmarshal(v.GetObject("params", "result").MarshalTo(nil), &res)
+		err = unmarshalResult(v, res)
 		if err != nil {
 			err = fmt.Errorf("failed to unmarshal new block notification: %w", err)
 		}
 	case types.NewTxsFeed, types.PendingTxsFeed:
 		res = &NewTxNotification{}
-		err = json.Unmarshal(v.GetObject("params", "result").MarshalTo(nil), &res)
+		err = unmarshalResult(v, res)
 		if err != nil {
 			err = fmt.Errorf("failed to unmarshal new tx notification: %w", err)
 		}
@@ -307,31 +512,47 @@ func (h *wsHandler) handleMessage(ctx context.Context, message []byte) error {
 		}
 	case types.TxReceiptsFeed:
 		res = &OnTxReceiptNotification{}
-		err = json.Unmarshal(v.GetObject("params", "result").MarshalTo(nil), &res)
+		err = unmarshalResult(v, res)
 		if err != nil {
 			err = fmt.Errorf("failed to unmarshal tx receipt notification: %w", err)
 		}
 	}
 
-	subscription.callback(ctx, err, res)
+	h.config.metrics().MessageReceived(h.url, string(subscription.feed))
+	if err != nil {
+		h.config.metrics().DecodeError(h.url, string(subscription.feed))
+	}
+
+	subscription.dispatcher.dispatch(ctx, err, res)
 
 	return nil
 }
 
 func (h *wsHandler) handlePendingResponse(id jsonrpc2.ID, resChan chan requestResponse, v *fastjson.Value) error {
-	defer func() {
-		close(resChan)
-		h.lock.Lock()
-		delete(h.pendingResponse, id)
-		h.lock.Unlock()
-	}()
+	rpcErr := responseError(v)
 
-	errMessage := v.GetObject("error")
-	if errMessage != nil {
-		code, _ := errMessage.Get("code").Int64()
-		data := json.RawMessage(errMessage.Get("data").GetStringBytes())
+	// the request is resolved by whoever removes it first, this response or failPending
+	h.lock.Lock()
+	pending, ok := h.pendingResponse[id]
+	delete(h.pendingResponse, id)
+	if ok && pending.subscription != nil && rpcErr == nil {
+		// the notifications may follow the response right away, before the subscriber has seen it,
+		// so the subscription is registered under its server ID here
+		if serverID := string(v.GetStringBytes("result")); serverID != "" && h.subscriptions[pending.subscription.id] == pending.subscription {
+			pending.subscription.serverID = serverID
+			h.serverIDs[serverID] = pending.subscription.id
+		}
+	}
+	h.lock.Unlock()
+	if !ok {
+		return nil
+	}
+
+	defer close(resChan)
+
+	if rpcErr != nil {
 		select {
-		case resChan <- requestResponse{Error: &RPCError{Code: code, Message: errMessage.Get("message").String(), Data: &data}}:
+		case resChan <- requestResponse{Error: rpcErr}:
 			return nil
 		default:
 			return nil
@@ -363,15 +584,67 @@ func (h *wsHandler) handlePendingResponse(id jsonrpc2.ID, resChan chan requestRe
 	return nil
 }
 
-// subscribe subscribes to a feed
-func (h *wsHandler) subscribe(ctx context.Context, f types.FeedType, subReq *jsonrpc2.Request) (chan requestResponse, error) {
+// unmarshalResult decodes the result of a notification, which must be an object
+func unmarshalResult(v *fastjson.Value, res any) error {
+	result := v.Get("params", "result")
+	if result == nil || result.Type() != fastjson.TypeObject {
+		return errors.New("result is not an object")
+	}
+
+	return json.Unmarshal(result.MarshalTo(nil), res)
+}
+
+// responseError returns the error of a response, or nil if it has none. Missing or mistyped fields
+// of the error are left empty, and an error which isn't an object becomes the message, so that
+// the response of a misbehaving endpoint still fails the request.
+func responseError(v *fastjson.Value) *RPCError {
+	errValue := v.Get("error")
+	if errValue == nil || errValue.Type() == fastjson.TypeNull {
+		return nil
+	}
+
+	if errValue.Type() != fastjson.TypeObject {
+		return &RPCError{Message: jsonString(errValue)}
+	}
+
+	rpcErr := &RPCError{
+		Code:    errValue.GetInt64("code"),
+		Message: jsonString(errValue.Get("message")),
+	}
+	if data := errValue.Get("data"); data != nil && data.Type() != fastjson.TypeNull {
+		raw := json.RawMessage(jsonString(data))
+		rpcErr.Data = &raw
+	}
+
+	return rpcErr
+}
+
+// jsonString returns the content of a JSON string, the JSON of any other value, or "" if there is no value
+func jsonString(v *fastjson.Value) string {
+	if v == nil {
+		return ""
+	}
+
+	if v.Type() == fastjson.TypeString {
+		return string(v.GetStringBytes())
+	}
+
+	return v.String()
+}
+
+// subscribe registers the subscription and sends its request. When resubscribing, the subscription
+// must still be registered, otherwise it was ended since the connection was lost and errSubscriptionEnded is returned.
+func (h *wsHandler) subscribe(ctx context.Context, subscription *wsSubscription, resubscribe bool) (chan requestResponse, error) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
+	registered, ok := h.subscriptions[subscription.id]
+	if resubscribe && registered != subscription {
+		return nil, errSubscriptionEnded
+	}
 	// check if already subscribed
-	_, ok := h.feeds[f]
-	if ok {
-		return nil, fmt.Errorf("already subscribed to %s", f)
+	if !resubscribe && ok {
+		return nil, fmt.Errorf("subscription %s to %s already exists", subscription.id, subscription.feed)
 	}
 
 	// connect if not connected
@@ -382,51 +655,86 @@ func (h *wsHandler) subscribe(ctx context.Context, f types.FeedType, subReq *jso
 	resChan := make(chan requestResponse, 1)
 
 	// add subscription
-	h.feeds[f] = feed{subscriptionID: ""}
-	h.pendingResponse[subReq.ID] = resChan
-
-	err := h.conn.WriteJSON(ctx, subReq)
+	subscription.serverID = ""
+	h.subscriptions[subscription.id] = subscription
+	priority := callOptionsFromContext(ctx).priority
+	h.pendingResponse[subscription.subReq.ID] = &pendingRequest{
+		resChan:      resChan,
+		req:          subscription.subReq,
+		subscription: subscription,
+		resubscribe:  resubscribe,
+		priority:     priority,
+	}
+
+	err := h.write(ctx, subscription.subReq, priority)
 	if err != nil {
-		return nil, fmt.Errorf("failed to write subscribe request for %s feed: %w", f, err)
+		// a subscription which fails to resubscribe is dropped or kept by resubscribeAll
+		if !resubscribe {
+			delete(h.subscriptions, subscription.id)
+		}
+		delete(h.pendingResponse, subscription.subReq.ID)
+		return nil, fmt.Errorf("failed to write subscribe request for %s feed: %w", subscription.feed, err)
 	}
 
 	return resChan, nil
 }
 
-// waitSubscriptionResponse waits for a subscription response
-func (h *wsHandler) waitSubscriptionResponse(ctx context.Context, resChan chan requestResponse, feedType types.FeedType, subReq *jsonrpc2.Request, callback CallbackFunc[any]) (string, error) {
-	wait := time.NewTimer(requestWaitTimeout)
+// waitSubscriptionResponse waits for a subscription response and returns the server ID of the subscription.
+// When a new subscription fails, it is removed; when a resubscription fails, resubscribeAll decides whether it is kept.
+func (h *wsHandler) waitSubscriptionResponse(ctx context.Context, resChan chan requestResponse, subscription *wsSubscription, resubscribe bool) (string, error) {
+	timeout := h.requestTimeout(ctx)
+	wait := time.NewTimer(timeout)
+	defer wait.Stop()
+
+	var err error
 	select {
 	case <-ctx.Done():
-		return "", nil
+		err = ctx.Err()
 	case res, ok := <-resChan:
 		if !ok {
-			return "", nil
+			err = ErrNoResponse
+			break
+		}
+		if res.Err != nil {
+			err = res.Err
+			break
 		}
 		if res.Error != nil {
-			return "", res.Error
+			err = res.Error
+			break
 		}
 
-		// add a subscription
 		h.lock.Lock()
 		defer h.lock.Unlock()
-		h.subscriptions[res.ID] = wsSubscription{subReq: subReq, callback: callback, feed: feedType}
-		h.feeds[feedType] = feed{subscriptionID: res.ID}
+
+		if h.subscriptions[subscription.id] != subscription {
+			return "", fmt.Errorf("subscription %s to %s was ended while subscribing", subscription.id, subscription.feed)
+		}
+
+		subscription.serverID = res.ID
+		h.serverIDs[res.ID] = subscription.id
 
 		return res.ID, nil
 	case <-wait.C:
-		h.lock.Lock()
-		defer h.lock.Unlock()
+		err = fmt.Errorf("didn't receive response for %s subscription request within %s: %w", subscription.feed, timeout, ErrRequestTimeout)
+	}
 
-		delete(h.pendingResponse, subReq.ID)
-		delete(h.feeds, feedType)
+	h.lock.Lock()
+	defer h.lock.Unlock()
 
-		return "", fmt.Errorf("didn't receive response for %s subscription request within %s", feedType, requestWaitTimeout)
+	delete(h.pendingResponse, subscription.subReq.ID)
+	if !resubscribe && h.subscriptions[subscription.id] == subscription {
+		delete(h.subscriptions, subscription.id)
+		if subscription.serverID != "" {
+			delete(h.serverIDs, subscription.serverID)
+		}
 	}
+
+	return "", err
 }
 
 // makes a single rpc request and expects a single response
-func (h *wsHandler) request(ctx context.Context, req *jsonrpc2.Request) (chan requestResponse, error) {
+func (h *wsHandler) request(ctx context.Context, req *jsonrpc2.Request, idempotent bool) (chan requestResponse, error) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
@@ -435,12 +743,20 @@ func (h *wsHandler) request(ctx context.Context, req *jsonrpc2.Request) (chan re
 		return nil, ErrNotConnected
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		attrEndpoint.String(h.url),
+		attrHandler.String(h.hst.String()),
+		attrRequestID.String(req.ID.String()),
+	)
+
 	resChan := make(chan requestResponse, 1)
 
-	h.pendingResponse[req.ID] = resChan
+	priority := callOptionsFromContext(ctx).priority
+	h.pendingResponse[req.ID] = &pendingRequest{resChan: resChan, req: req, idempotent: idempotent, priority: priority}
 
-	err := h.conn.WriteJSON(ctx, req)
+	err := h.write(ctx, req, priority)
 	if err != nil {
+		delete(h.pendingResponse, req.ID)
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
@@ -449,15 +765,27 @@ func (h *wsHandler) request(ctx context.Context, req *jsonrpc2.Request) (chan re
 
 // wait requestResponse waits for a request response and returns the response or error
 func (h *wsHandler) waitRequestResponse(ctx context.Context, resChan chan requestResponse, req *jsonrpc2.Request) (*json.RawMessage, error) {
-	wait := time.NewTimer(requestWaitTimeout)
+	wait := time.NewTimer(h.requestTimeout(ctx))
+	defer wait.Stop()
+
 	select {
 	case <-ctx.Done():
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		// don't send the request again after a reconnect
+		delete(h.pendingResponse, req.ID)
+
 		return nil, ctx.Err()
 	case res, ok := <-resChan:
 		if !ok {
 			return nil, ErrNoResponse
 		}
 
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
 		if res.Error != nil {
 			return nil, res.Error
 		}
@@ -470,71 +798,188 @@ func (h *wsHandler) waitRequestResponse(ctx context.Context, resChan chan reques
 
 		delete(h.pendingResponse, req.ID)
 
-		return nil, fmt.Errorf("request timed out")
+		return nil, ErrRequestTimeout
 	}
 }
 
+// requestTimeout returns how long the request made with ctx waits for its response, which is until the deadline of its call if it has one
+func (h *wsHandler) requestTimeout(ctx context.Context) time.Duration {
+	if deadline := callOptionsFromContext(ctx).deadline; !deadline.IsZero() {
+		return time.Until(deadline)
+	}
+
+	return h.config.RequestTimeout
+}
+
+// write writes the request to the connection, ahead of the waiting requests if it has a high priority
+func (h *wsHandler) write(ctx context.Context, req *jsonrpc2.Request, priority Priority) error {
+	if writer, ok := h.conn.(ws.PriorityWriter); ok && priority == PriorityHigh {
+		return writer.WritePriorityJSON(ctx, req)
+	}
+
+	return h.conn.WriteJSON(ctx, req)
+}
+
+// failPending fails the requests waiting for their responses with ConnectionLostError. When keepIdempotent is set,
+// the idempotent requests are kept to be sent again by replayPending.
+// The subscriptions whose subscribe requests fail are removed, except for the ones being resubscribed,
+// which stay registered to be resubscribed after the next reconnect.
+func (h *wsHandler) failPending(cause error, keepIdempotent bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for id, pending := range h.pendingResponse {
+		if keepIdempotent && pending.idempotent {
+			pending.replay = true
+			continue
+		}
+
+		delete(h.pendingResponse, id)
+
+		subscription := pending.subscription
+		if subscription != nil && !pending.resubscribe && h.subscriptions[subscription.id] == subscription {
+			delete(h.subscriptions, subscription.id)
+		}
+
+		select {
+		case pending.resChan <- requestResponse{Err: &ConnectionLostError{Endpoint: h.url, Err: cause}}:
+		default:
+		}
+	}
+}
+
+// replayPending sends the idempotent requests kept by failPending again on the new connection
+func (h *wsHandler) replayPending(ctx context.Context) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for id, pending := range h.pendingResponse {
+		if !pending.replay {
+			continue
+		}
+		pending.replay = false
+
+		err := h.write(ctx, pending.req, pending.priority)
+		if err != nil {
+			delete(h.pendingResponse, id)
+
+			select {
+			case pending.resChan <- requestResponse{Err: &ConnectionLostError{Endpoint: h.url, Err: err}}:
+			default:
+			}
+		}
+	}
+}
+
+// startResubscribe resubscribes to all feeds after a reconnect. The resubscription started after the previous
+// reconnect is superseded, and the new one starts once it has returned, so that they never run together.
+func (h *wsHandler) startResubscribe(ctx context.Context) {
+	h.lock.Lock()
+	if h.cancelResubscribe != nil {
+		h.cancelResubscribe()
+	}
+	previous := h.resubscribed
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.cancelResubscribe, h.resubscribed = cancel, done
+	h.lock.Unlock()
+
+	go func() {
+		defer close(done)
+		defer cancel()
+
+		if previous != nil {
+			<-previous
+		}
+		h.resubscribeAll(ctx)
+	}()
+}
+
 func (h *wsHandler) resubscribeAll(ctx context.Context) {
 	h.lock.Lock()
 
-	subCopy := make([]wsSubscription, 0, len(h.subscriptions))
+	// the subscriptions stay registered until they are resubscribed, so that an Unsubscribe in the meantime ends them
+	subCopy := make([]*wsSubscription, 0, len(h.subscriptions))
 	for _, subscription := range h.subscriptions {
+		subscription.serverID = ""
 		subCopy = append(subCopy, subscription)
 	}
-	h.subscriptions = make(map[string]wsSubscription)
-	h.feeds = make(map[types.FeedType]feed)
+	h.serverIDs = make(map[string]string)
 
 	h.lock.Unlock()
 
-loop:
-	for _, subscription := range subCopy {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			// create new subscription ID
-			subscription.subReq.ID = randomID()
-			resChan, err := h.subscribe(ctx, subscription.feed, subscription.subReq)
-			if err != nil {
-				h.config.Logger.Errorf("failed to resubscribe to %s feed: %s", subscription.feed, err)
-				continue loop
+	for i, subscription := range subCopy {
+		if ctx.Err() != nil {
+			select {
+			case <-h.stop:
+				// the handler is closed, so the remaining subscriptions are dropped
+				for _, remaining := range subCopy[i:] {
+					remaining.dispatcher.close()
+				}
+			default:
+				// superseded after another reconnect, which resubscribes the remaining subscriptions
 			}
-			_, err = h.waitSubscriptionResponse(ctx, resChan, subscription.feed, subscription.subReq, subscription.callback)
-			if err != nil {
-				h.config.Logger.Errorf("failed to resubscribe to %s feed: %s", subscription.feed, err)
+			return
+		}
+
+		// create new subscription ID
+		subscription.subReq.ID = randomID()
+		resChan, err := h.subscribe(ctx, subscription, true)
+		if errors.Is(err, errSubscriptionEnded) {
+			continue
+		}
+		if err == nil {
+			_, err = h.waitSubscriptionResponse(ctx, resChan, subscription, true)
+		}
+		if err != nil && h.resubscribeInterrupted(ctx, err) {
+			// the connection was lost again, so this and the remaining subscriptions
+			// stay registered and are resubscribed after the next reconnect
+			return
+		}
+		if err != nil {
+			h.logger.Error("failed to resubscribe", slog.String(logKeyFeed, string(subscription.feed)),
+				slog.String(logKeySubscription, subscription.id), slog.String(logKeyRequestID, subscription.subReq.ID.String()), slog.Any(logKeyError, err))
+
+			h.lock.Lock()
+			if h.subscriptions[subscription.id] == subscription {
+				h.dropSubscription(subscription)
+			} else {
+				subscription.dispatcher.close()
 			}
+			h.lock.Unlock()
 		}
+
+		h.config.emit(Event{Type: EventResubscribed, Endpoint: h.url, Feed: subscription.feed, Subscription: subscription.id, Err: err})
 	}
 }
 
-func (h *wsHandler) unsubscribe(f types.FeedType) error {
+// resubscribeInterrupted reports whether a resubscription failed because the connection was lost
+// or the resubscription was superseded, rather than because the subscription was rejected
+func (h *wsHandler) resubscribeInterrupted(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, ErrConnectionLost) || !h.Healthy()
+}
+
+func (h *wsHandler) unsubscribe(id string) error {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
-	if h.conn == nil {
-		return backoff.Permanent(fmt.Errorf("connection is not established"))
-	}
-
-	_, ok := h.feeds[f]
+	subscription, ok := h.subscriptions[id]
 	if !ok {
 		// no need to unsubscribe
 		return nil
 	}
 
-	var subscriptionID string
-	for id, subscription := range h.subscriptions {
-		if subscription.feed != f {
-			continue
-		}
-		subscriptionID = id
-		break
+	if h.conn == nil || !h.Healthy() {
+		// the subscriptions on the server end with the connection, so it is only dropped here
+		h.dropSubscription(subscription)
+		return nil
 	}
 
-	if subscriptionID == "" {
-		return fmt.Errorf("no subscription ID is defined for %s yet", types.NewTxsFeed)
+	if subscription.serverID == "" {
+		return fmt.Errorf("no subscription ID is defined for %s yet", subscription.feed)
 	}
 
-	raw, err := json.Marshal([]interface{}{subscriptionID})
+	raw, err := json.Marshal([]interface{}{subscription.serverID})
 	if err != nil {
 		return backoff.Permanent(fmt.Errorf("failed to marshal params: %w", err))
 	}
@@ -545,16 +990,24 @@ func (h *wsHandler) unsubscribe(f types.FeedType) error {
 		Params: (*json.RawMessage)(&raw),
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.UnsubscribeTimeout)
 	defer cancel()
 
 	err = h.conn.WriteJSON(ctx, unsubscribeRequest)
 	if err != nil {
-		return fmt.Errorf("failed to write unsubscribe request for %s feed: %w", f, err)
+		return fmt.Errorf("failed to write unsubscribe request for %s feed: %w", subscription.feed, err)
 	}
 
-	delete(h.feeds, f)
-	delete(h.subscriptions, subscriptionID)
+	h.dropSubscription(subscription)
 
 	return nil
 }
+
+// dropSubscription removes the subscription and stops its dispatcher. It must be called with the lock held.
+func (h *wsHandler) dropSubscription(subscription *wsSubscription) {
+	subscription.dispatcher.close()
+	delete(h.subscriptions, subscription.id)
+	if subscription.serverID != "" {
+		delete(h.serverIDs, subscription.serverID)
+	}
+}
@@ -0,0 +1,523 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fastjson"
+
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/bxtest"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testDropServer starts a websocket server which drops the first connection when it receives
+// a request, and responds to the requests on the following connections
+func testDropServer(t *testing.T) string {
+	t.Helper()
+
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		connection := connections.Add(1)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			v, err := fastjson.ParseBytes(message)
+			if err != nil || len(v.GetStringBytes("id")) == 0 {
+				continue
+			}
+
+			if connection == 1 {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+
+			response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":{"connection":%d}}`, v.GetStringBytes("id"), connection)
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(response)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWSRequestConnectionLost(t *testing.T) {
+	t.Run("fail", func(t *testing.T) {
+		url := testDropServer(t)
+
+		c, err := NewClient(context.Background(), &Config{WSGatewayURL: url, AuthHeader: "auth"})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		start := time.Now()
+		_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.ErrorIs(t, err, ErrConnectionLost)
+		require.Less(t, time.Since(start), defaultRequestTimeout/2)
+
+		var connectionLost *ConnectionLostError
+		require.True(t, errors.As(err, &connectionLost))
+		require.Equal(t, url, connectionLost.Endpoint)
+
+		// wait for the reconnect so that the client closes cleanly
+		require.Eventually(t, func() bool { return c.State() == StateConnected }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		c, err := NewClient(context.Background(), &Config{WSGatewayURL: testDropServer(t), AuthHeader: "auth"})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		res, err := c.SendTx(WithIdempotent(context.Background()), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		// the response comes from the second connection
+		require.JSONEq(t, `{"connection":2}`, string(*res))
+	})
+
+	t.Run("read_only", func(t *testing.T) {
+		require.True(t, isIdempotent(context.Background(), RPCBSCGetBundlePrice))
+		require.False(t, isIdempotent(context.Background(), jsonrpc.RPCTx))
+		require.True(t, isIdempotent(WithIdempotent(context.Background()), jsonrpc.RPCTx))
+	})
+}
+
+// testFeedResults are the feeds decoded by the WS handler, with the type of their notifications
+var testFeedResults = map[types.FeedType]any{
+	types.NewTxsFeed:            &NewTxNotification{},
+	types.PendingTxsFeed:        &NewTxNotification{},
+	types.BDNBlocksFeed:         &OnBdnBlockNotification{},
+	types.NewBlocksFeed:         &OnBdnBlockNotification{},
+	types.OnBlockFeed:           &OnBlockNotification{},
+	types.TxReceiptsFeed:        &OnTxReceiptNotification{},
+	types.TransactionStatusFeed: &OnTxStatusNotification{},
+}
+
+// testDispatched is a notification dispatched to a subscription
+type testDispatched struct {
+	feed   types.FeedType
+	err    error
+	result any
+}
+
+// testNotificationFrame returns the recorded WS notification of the feed in testdata
+func testNotificationFrame(tb testing.TB, feed types.FeedType) []byte {
+	tb.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", "notifications", string(feed)+".json"))
+	require.NoError(tb, err)
+
+	return b
+}
+
+// testWSHandler returns a WS handler without a connection
+func testWSHandler() *wsHandler {
+	config := &Config{Logger: &NoopLogger{}, Slog: slog.New(NewLoggerHandler(&NoopLogger{})), DispatchQueueSize: 10}
+
+	return &wsHandler{
+		hst:             handlerSourceTypeGatewayWS,
+		url:             "ws://localhost/ws",
+		config:          config,
+		logger:          config.Slog,
+		subscriptions:   make(map[string]*wsSubscription),
+		serverIDs:       make(map[string]string),
+		pendingResponse: make(map[jsonrpc2.ID]*pendingRequest),
+		lock:            &sync.Mutex{},
+	}
+}
+
+// testMessageHandler returns a WS handler with a subscription to each feed, under the server ID of the recorded
+// notification of the feed. The notifications stay in the queues of the subscriptions, with no goroutine passing
+// them to callbacks, so that they can be read from the queues right after they are dispatched.
+func testMessageHandler(tb testing.TB) *wsHandler {
+	tb.Helper()
+
+	h := testWSHandler()
+	for feed := range testFeedResults {
+		serverID := string(fastjson.MustParseBytes(testNotificationFrame(tb, feed)).GetStringBytes("params", "subscription"))
+		subscription := &wsSubscription{
+			id:       string(feed),
+			serverID: serverID,
+			feed:     feed,
+			dispatcher: &dispatcher{
+				id:           string(feed),
+				feed:         feed,
+				metrics:      noopMetrics{},
+				queue:        make(chan dispatchedNotification, 1),
+				overflow:     make(chan struct{}),
+				stop:         make(chan struct{}),
+				overflowOnce: &sync.Once{},
+				closeOnce:    &sync.Once{},
+			},
+		}
+		h.subscriptions[subscription.id] = subscription
+		h.serverIDs[serverID] = subscription.id
+	}
+
+	return h
+}
+
+// dispatched removes the notifications from the queues of the subscriptions and returns them
+func dispatched(h *wsHandler) []testDispatched {
+	var notifications []testDispatched
+	for _, subscription := range h.subscriptions {
+		for len(subscription.dispatcher.queue) > 0 {
+			n := <-subscription.dispatcher.queue
+			notifications = append(notifications, testDispatched{feed: subscription.feed, err: n.err, result: n.result})
+		}
+	}
+
+	return notifications
+}
+
+// receiveResponses returns the responses sent to a request and whether its channel is closed, without waiting
+func receiveResponses(resChan chan requestResponse) ([]requestResponse, bool) {
+	var responses []requestResponse
+	for {
+		select {
+		case res, ok := <-resChan:
+			if !ok {
+				return responses, true
+			}
+			responses = append(responses, res)
+		default:
+			return responses, false
+		}
+	}
+}
+
+// requireGoroutines fails if there are still more than n goroutines after a second
+func requireGoroutines(t *testing.T, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines are left running, expected at most %d", runtime.NumGoroutine(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func FuzzHandleMessage(f *testing.F) {
+	for feed := range testFeedResults {
+		f.Add(testNotificationFrame(f, feed))
+	}
+
+	txReceipts := string(fastjson.MustParseBytes(testNotificationFrame(f, types.TxReceiptsFeed)).GetStringBytes("params", "subscription"))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"pending","result":"0xa3f0c1d2"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"pending","error":{"code":-32000,"message":"nonce too low"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"unknown","result":{"tx_hash":"0x01"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"subscribe","params":{"subscription":"unknown","result":{}}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"subscribe","params":{"subscription":"` + txReceipts + `"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"subscribe","params":{"subscription":"` + txReceipts + `","result":[null]}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"subscribe","params":{"subscription":"` + txReceipts + `","result":{"logs":"0x"}}}`))
+	f.Add([]byte(`[{"jsonrpc":"2.0"}]`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"subscribe"`))
+
+	h := testMessageHandler(f)
+	pendingID := jsonrpc2.ID{Str: "pending", IsString: true}
+
+	f.Fuzz(func(t *testing.T, message []byte) {
+		resChan := make(chan requestResponse, 1)
+		h.lock.Lock()
+		h.pendingResponse[pendingID] = &pendingRequest{resChan: resChan}
+		h.lock.Unlock()
+		defer func() {
+			h.lock.Lock()
+			delete(h.pendingResponse, pendingID)
+			h.lock.Unlock()
+		}()
+
+		goroutines := runtime.NumGoroutine()
+		err := h.handleMessage(context.Background(), message)
+		notifications := dispatched(h)
+		requireGoroutines(t, goroutines)
+
+		v, parseErr := fastjson.ParseBytes(message)
+		if parseErr != nil {
+			require.Error(t, err)
+			require.Empty(t, notifications)
+			return
+		}
+		require.NoError(t, err)
+
+		// the response to the pending request resolves it, and only it
+		h.lock.Lock()
+		_, stillPending := h.pendingResponse[pendingID]
+		h.lock.Unlock()
+		responses, closed := receiveResponses(resChan)
+		if string(v.GetStringBytes("id")) == pendingID.Str {
+			require.False(t, stillPending)
+			require.True(t, closed)
+			require.LessOrEqual(t, len(responses), 1)
+			require.Empty(t, notifications)
+			return
+		}
+		require.True(t, stillPending)
+		require.False(t, closed)
+		require.Empty(t, responses)
+
+		// the notifications to unknown subscriptions are ignored
+		serverID := string(v.GetStringBytes("params", "subscription"))
+		h.lock.Lock()
+		id, known := h.serverIDs[serverID]
+		h.lock.Unlock()
+		if string(v.GetStringBytes("method")) != "subscribe" || !known {
+			require.Empty(t, notifications)
+			return
+		}
+
+		// the others are passed to their subscription with a result of the type of its feed,
+		// and an error if the result isn't an object which can be decoded
+		require.Len(t, notifications, 1)
+		feed := h.subscriptions[id].feed
+		require.Equal(t, feed, notifications[0].feed)
+		require.NotNil(t, notifications[0].result)
+		require.IsType(t, testFeedResults[feed], notifications[0].result)
+		if feed != types.OnBlockFeed && feed != types.TransactionStatusFeed && v.GetObject("params", "result") == nil {
+			require.Error(t, notifications[0].err)
+		}
+	})
+}
+
+func FuzzHandlePendingResponse(f *testing.F) {
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","result":"a3f0c1d2-58e4-4c6b-9a8e-1f2d3c4b5a69"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","result":{"tx_hash":"0x01"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","result":""}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":null,"result":true}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":{"code":-32000,"message":"nonce too low","data":"invalid transaction"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":{"code":"-32000"}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":{"message":{"reason":"reverted"},"data":{"code":3}}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":"rate limit reached","result":"a3f0c1d2"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":"1","error":[]}`))
+
+	id := jsonrpc2.ID{Str: "1", IsString: true}
+
+	f.Fuzz(func(t *testing.T, response []byte) {
+		v, err := fastjson.ParseBytes(response)
+		if err != nil {
+			t.Skip("not JSON")
+		}
+
+		h := testWSHandler()
+		subscription := &wsSubscription{id: "1", feed: types.NewTxsFeed}
+		resChan := make(chan requestResponse, 1)
+		h.subscriptions[subscription.id] = subscription
+		h.pendingResponse[id] = &pendingRequest{resChan: resChan, subscription: subscription}
+
+		require.NoError(t, h.handlePendingResponse(id, resChan, v))
+
+		responses, closed := receiveResponses(resChan)
+		require.True(t, closed)
+		require.LessOrEqual(t, len(responses), 1)
+		require.NotContains(t, h.pendingResponse, id)
+
+		// a second response to the same request is ignored
+		require.NoError(t, h.handlePendingResponse(id, resChan, v))
+
+		errValue := v.Get("error")
+		result := v.Get("result")
+		switch {
+		case errValue != nil && errValue.Type() != fastjson.TypeNull:
+			// any error fails the request, even if it isn't a JSON-RPC error object
+			require.Len(t, responses, 1)
+			require.NotNil(t, responses[0].Error)
+			require.NotEmpty(t, responses[0].Error.Error())
+			require.Empty(t, h.serverIDs)
+		case len(v.GetStringBytes("result")) > 0:
+			require.Len(t, responses, 1)
+			require.Equal(t, string(v.GetStringBytes("result")), responses[0].ID)
+			require.Equal(t, responses[0].ID, subscription.serverID)
+			require.Equal(t, subscription.id, h.serverIDs[subscription.serverID])
+		case result != nil:
+			// the result is passed on as it is, which fastjson accepts with e.g. control characters in strings
+			require.Len(t, responses, 1)
+			require.Nil(t, responses[0].Error)
+			if json.Valid(response) {
+				require.True(t, json.Valid(responses[0].Result), string(responses[0].Result))
+			}
+		default:
+			require.Empty(t, responses)
+		}
+	})
+}
+
+func TestResponseError(t *testing.T) {
+	data := func(s string) *json.RawMessage {
+		raw := json.RawMessage(s)
+		return &raw
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		expected *RPCError
+	}{
+		{
+			name:     "error",
+			response: `{"id":"1","error":{"code":-32000,"message":"nonce too low","data":"invalid transaction"}}`,
+			expected: &RPCError{Code: -32000, Message: "nonce too low", Data: data("invalid transaction")},
+		},
+		{
+			name:     "no error",
+			response: `{"id":"1","result":{"tx_hash":"0x01"}}`,
+		},
+		{
+			name:     "null",
+			response: `{"id":"1","error":null,"result":true}`,
+		},
+		{
+			name:     "no message",
+			response: `{"id":"1","error":{"code":-32000}}`,
+			expected: &RPCError{Code: -32000},
+		},
+		{
+			name:     "no code",
+			response: `{"id":"1","error":{"message":"nonce too low"}}`,
+			expected: &RPCError{Message: "nonce too low"},
+		},
+		{
+			name:     "string code",
+			response: `{"id":"1","error":{"code":"-32000","message":"nonce too low"}}`,
+			expected: &RPCError{Message: "nonce too low"},
+		},
+		{
+			name:     "object message and data",
+			response: `{"id":"1","error":{"code":3,"message":{"reason":"reverted"},"data":{"code":3}}}`,
+			expected: &RPCError{Code: 3, Message: `{"reason":"reverted"}`, Data: data(`{"code":3}`)},
+		},
+		{
+			name:     "string",
+			response: `{"id":"1","error":"rate limit reached"}`,
+			expected: &RPCError{Message: "rate limit reached"},
+		},
+		{
+			name:     "array",
+			response: `{"id":"1","error":[1,2]}`,
+			expected: &RPCError{Message: "[1,2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, responseError(fastjson.MustParse(tt.response)))
+		})
+	}
+}
+
+func TestWSUnsubscribeDisconnected(t *testing.T) {
+	h := testMessageHandler(t)
+	h.config.UnsubscribeTimeout = time.Second
+	h.setState(StateReconnecting)
+
+	subscription := h.subscriptions[string(types.NewTxsFeed)]
+
+	// the connection is down, so the subscription is dropped without waiting to tell the server
+	start := time.Now()
+	require.NoError(t, h.Unsubscribe(subscription.id))
+	require.Less(t, time.Since(start), h.config.UnsubscribeTimeout)
+
+	require.NotContains(t, h.subscriptions, subscription.id)
+	require.NotContains(t, h.serverIDs, subscription.serverID)
+
+	select {
+	case <-subscription.dispatcher.stop:
+	default:
+		require.Fail(t, "dispatcher of the dropped subscription is still running")
+	}
+}
+
+func TestWSUnsubscribeBeforeResubscribe(t *testing.T) {
+	h := testMessageHandler(t)
+	h.config.UnsubscribeTimeout = time.Second
+	subscription := h.subscriptions[string(types.NewTxsFeed)]
+
+	// the connection was lost, and the subscription is ended before resubscribeAll gets to it
+	h.setState(StateReconnecting)
+	require.NoError(t, h.Unsubscribe(subscription.id))
+
+	_, err := h.subscribe(context.Background(), subscription, true)
+	require.ErrorIs(t, err, errSubscriptionEnded)
+	require.NotContains(t, h.subscriptions, subscription.id)
+}
+
+func TestWSResubscribeConnectionLost(t *testing.T) {
+	config, s := testStandIn(t, wsGatewayUrl)
+
+	// the connection is dropped again while the first two resubscriptions wait for their responses
+	var subscribes atomic.Int32
+	s.Handle(jsonrpc.RPCSubscribe, func(ctx context.Context, req *bxtest.Request) (any, error) {
+		if n := subscribes.Add(1); n == 2 || n == 3 {
+			s.Disconnect()
+		}
+		return true, nil
+	})
+
+	var failed atomic.Int32
+	config.OnEvent = func(event Event) {
+		if event.Type == EventResubscribed && event.Err != nil {
+			failed.Add(1)
+		}
+	}
+
+	c, err := NewClient(context.Background(), config)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	txs, err := c.OnNewTxChan(ctx, nil)
+	require.NoError(t, err)
+
+	receiveStandInTx := func() {
+		select {
+		case n := <-txs:
+			require.NoError(t, n.Err)
+			require.NotEmpty(t, n.Result.RawTx)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for new tx")
+		}
+	}
+	receiveStandInTx()
+
+	s.Disconnect()
+
+	// the subscription survives both drops and is resubscribed on the fourth connection
+	require.Eventually(t, func() bool { return subscribes.Load() >= 4 }, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.WaitSubscriptions(ctx, types.NewTxsFeed, 1))
+	require.Zero(t, failed.Load())
+
+	for len(txs) > 0 {
+		<-txs
+	}
+	receiveStandInTx()
+}
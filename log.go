@@ -1,5 +1,23 @@
 package bloxroute_sdk_go
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// keys of the attributes of the log lines
+const (
+	logKeyEndpoint     = "endpoint"
+	logKeyHandler      = "handler"
+	logKeyFeed         = "feed"
+	logKeySubscription = "subscription"
+	logKeyRequestID    = "request_id"
+	logKeyAttempt      = "attempt"
+	logKeyError        = "error"
+)
+
 type NoopLogger struct{}
 
 func (n *NoopLogger) Debug(...interface{}) {}
@@ -17,3 +35,94 @@ func (n *NoopLogger) Warnf(string, ...interface{}) {}
 func (n *NoopLogger) Error(...interface{}) {}
 
 func (n *NoopLogger) Errorf(string, ...interface{}) {}
+
+// loggerHandler is a slog.Handler which writes the log lines to a Logger,
+// with the attributes appended to the message as key=value pairs
+type loggerHandler struct {
+	logger Logger
+	// attrs are the attributes added with WithAttrs, already formatted
+	attrs string
+	// group is the prefix of the keys of the attributes added after WithGroup
+	group string
+}
+
+// NewLoggerHandler returns a slog.Handler which writes to the Logger, e.g. to pass a Logger
+// to code which logs with slog. Logger is used this way when Config.Slog isn't set.
+func NewLoggerHandler(logger Logger) slog.Handler {
+	return &loggerHandler{logger: logger}
+}
+
+func (h *loggerHandler) Enabled(context.Context, slog.Level) bool {
+	_, noop := h.logger.(*NoopLogger)
+	return !noop
+}
+
+func (h *loggerHandler) Handle(_ context.Context, r slog.Record) error {
+	line := &strings.Builder{}
+	line.WriteString(r.Message)
+	line.WriteString(h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		writeLogAttr(line, h.group, attr)
+		return true
+	})
+
+	switch {
+	case r.Level >= slog.LevelError:
+		h.logger.Error(line.String())
+	case r.Level >= slog.LevelWarn:
+		h.logger.Warn(line.String())
+	case r.Level >= slog.LevelInfo:
+		h.logger.Info(line.String())
+	default:
+		h.logger.Debug(line.String())
+	}
+
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	line := &strings.Builder{}
+	line.WriteString(h.attrs)
+	for _, attr := range attrs {
+		writeLogAttr(line, h.group, attr)
+	}
+
+	return &loggerHandler{logger: h.logger, attrs: line.String(), group: h.group}
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &loggerHandler{logger: h.logger, attrs: h.attrs, group: h.group + name + "."}
+}
+
+// writeLogAttr appends the attribute to the line as " key=value", flattening groups into dotted keys
+func writeLogAttr(line *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			group += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			writeLogAttr(line, group, a)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if strings.ContainsAny(value, " =\"") {
+		value = fmt.Sprintf("%q", value)
+	}
+
+	line.WriteString(" ")
+	line.WriteString(group)
+	line.WriteString(attr.Key)
+	line.WriteString("=")
+	line.WriteString(value)
+}
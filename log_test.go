@@ -0,0 +1,127 @@
+package bloxroute_sdk_go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testLogger records the lines written to each level
+type testLogger struct {
+	NoopLogger
+	lock  sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) record(level string, args []interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.lines = append(l.lines, level+": "+fmt.Sprint(args...))
+}
+
+func (l *testLogger) Debug(args ...interface{}) { l.record("debug", args) }
+
+func (l *testLogger) Info(args ...interface{}) { l.record("info", args) }
+
+func (l *testLogger) Warn(args ...interface{}) { l.record("warn", args) }
+
+func (l *testLogger) Error(args ...interface{}) { l.record("error", args) }
+
+// testLogBuffer is a log output which can be read while it is written to
+type testLogBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *testLogBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// records returns the JSON log records written so far
+func (b *testLogBuffer) records(t *testing.T) []map[string]any {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		record := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLoggerHandler(t *testing.T) {
+	logger := &testLogger{}
+	log := slog.New(NewLoggerHandler(logger)).With(slog.String(logKeyEndpoint, "wss://api.blxrbdn.com/ws"))
+
+	log.Error("failed to resubscribe", slog.String(logKeyFeed, string(types.NewTxsFeed)), slog.Any(logKeyError, errors.New("no response")))
+	log.WithGroup("stream").Warn("reopening", slog.Int(logKeyAttempt, 2))
+	log.Info("connected")
+	log.Debug("closed")
+
+	require.Equal(t, []string{
+		`error: failed to resubscribe endpoint=wss://api.blxrbdn.com/ws feed=newTxs error="no response"`,
+		"warn: reopening endpoint=wss://api.blxrbdn.com/ws stream.attempt=2",
+		"info: connected endpoint=wss://api.blxrbdn.com/ws",
+		"debug: closed endpoint=wss://api.blxrbdn.com/ws",
+	}, logger.lines)
+
+	// nothing is formatted for the default logger
+	require.False(t, NewLoggerHandler(&NoopLogger{}).Enabled(context.Background(), slog.LevelError))
+}
+
+func TestSlog(t *testing.T) {
+	output := &testLogBuffer{}
+	url := testGRPCGateway(t, &testGatewayServer{})
+
+	c, err := NewClient(context.Background(), &Config{
+		GRPCGatewayURL: url,
+		AuthHeader:     "auth",
+		Slog:           slog.New(slog.NewJSONHandler(output, nil)),
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	// the first stream fails after one transaction and is reopened
+	sub, err := c.SubscribeNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+	require.NoError(t, err)
+
+	var record map[string]any
+	require.Eventually(t, func() bool {
+		for _, r := range output.records(t) {
+			if r["msg"] == "failed to receive from stream, reopening" {
+				record = r
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "ERROR", record["level"])
+	require.Equal(t, url, record[logKeyEndpoint])
+	require.Equal(t, handlerSourceTypeGatewayGRPC.String(), record[logKeyHandler])
+	require.Equal(t, string(types.NewTxsFeed), record[logKeyFeed])
+	require.Equal(t, sub.ID(), record[logKeySubscription])
+	require.NotEmpty(t, record[logKeyError])
+}
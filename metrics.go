@@ -0,0 +1,60 @@
+package bloxroute_sdk_go
+
+import "time"
+
+// Metrics records the instrumentation of the client, e.g. with the Prometheus or OpenTelemetry
+// implementations of the metrics/prommetrics and metrics/otelmetrics packages. The methods are
+// called on the hot paths of the connections, so they must be fast and must not block.
+type Metrics interface {
+	// MessageReceived counts a message of the feed received from the endpoint
+	MessageReceived(endpoint, feed string)
+
+	// DecodeError counts a message of the feed received from the endpoint which couldn't be decoded
+	DecodeError(endpoint, feed string)
+
+	// CallbackDuration records how long the callback of a subscription to the feed took for a notification
+	CallbackDuration(feed string, duration time.Duration)
+
+	// QueueDepth records the number of notifications waiting for the callback of a subscription to the feed,
+	// each time a notification is queued
+	QueueDepth(feed string, depth int)
+
+	// NotificationDropped counts a notification of the feed dropped because the queue of its subscription was full
+	NotificationDropped(feed string)
+
+	// RequestDuration records how long a request of the method to the endpoint took, and its error if it failed
+	RequestDuration(endpoint, method string, duration time.Duration, err error)
+
+	// Reconnect counts an attempt to reconnect to the endpoint, or to reopen one of its gRPC streams
+	Reconnect(endpoint string)
+
+	// Disconnected records how long the connection to the endpoint was lost, once it is established again
+	Disconnected(endpoint string, duration time.Duration)
+}
+
+// metrics returns the Metrics of the config, which record nothing if none are set
+func (c *Config) metrics() Metrics {
+	if c.Metrics == nil {
+		return noopMetrics{}
+	}
+
+	return c.Metrics
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) MessageReceived(string, string) {}
+
+func (noopMetrics) DecodeError(string, string) {}
+
+func (noopMetrics) CallbackDuration(string, time.Duration) {}
+
+func (noopMetrics) QueueDepth(string, int) {}
+
+func (noopMetrics) NotificationDropped(string) {}
+
+func (noopMetrics) RequestDuration(string, string, time.Duration, error) {}
+
+func (noopMetrics) Reconnect(string) {}
+
+func (noopMetrics) Disconnected(string, time.Duration) {}
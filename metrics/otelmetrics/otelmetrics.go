@@ -0,0 +1,108 @@
+// Package otelmetrics records the metrics of the SDK client with OpenTelemetry
+package otelmetrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+)
+
+var _ sdk.Metrics = (*Metrics)(nil)
+
+// Metrics implements sdk.Metrics with OpenTelemetry instruments
+type Metrics struct {
+	messages            metric.Int64Counter
+	decodeErrors        metric.Int64Counter
+	callbackDuration    metric.Float64Histogram
+	queueDepth          metric.Int64Histogram
+	dropped             metric.Int64Counter
+	requestDuration     metric.Float64Histogram
+	reconnects          metric.Int64Counter
+	disconnectedSeconds metric.Float64Counter
+}
+
+// New creates the instruments with the meter, e.g. otel.Meter("github.com/bloXroute-Labs/bloxroute-sdk-go")
+func New(meter metric.Meter) (*Metrics, error) {
+	m := &Metrics{}
+
+	var err, e error
+	m.messages, e = meter.Int64Counter("bloxroute.sdk.messages_received",
+		metric.WithDescription("Number of feed messages received per endpoint and feed."))
+	err = errors.Join(err, e)
+	m.decodeErrors, e = meter.Int64Counter("bloxroute.sdk.decode_errors",
+		metric.WithDescription("Number of feed messages which couldn't be decoded per endpoint and feed."))
+	err = errors.Join(err, e)
+	m.callbackDuration, e = meter.Float64Histogram("bloxroute.sdk.callback.duration", metric.WithUnit("s"),
+		metric.WithDescription("Time the subscription callbacks took per notification."))
+	err = errors.Join(err, e)
+	m.queueDepth, e = meter.Int64Histogram("bloxroute.sdk.dispatch_queue.depth",
+		metric.WithDescription("Number of notifications waiting for the callback when a notification is queued."))
+	err = errors.Join(err, e)
+	m.dropped, e = meter.Int64Counter("bloxroute.sdk.notifications_dropped",
+		metric.WithDescription("Number of notifications dropped because the dispatch queue of their subscription was full."))
+	err = errors.Join(err, e)
+	m.requestDuration, e = meter.Float64Histogram("bloxroute.sdk.request.duration", metric.WithUnit("s"),
+		metric.WithDescription("Time the requests took per endpoint, method and result."))
+	err = errors.Join(err, e)
+	m.reconnects, e = meter.Int64Counter("bloxroute.sdk.reconnects",
+		metric.WithDescription("Number of attempts to reconnect to an endpoint or to reopen one of its gRPC streams."))
+	err = errors.Join(err, e)
+	m.disconnectedSeconds, e = meter.Float64Counter("bloxroute.sdk.disconnected.duration", metric.WithUnit("s"),
+		metric.WithDescription("Time spent disconnected from an endpoint."))
+	err = errors.Join(err, e)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// MessageReceived implements sdk.Metrics
+func (m *Metrics) MessageReceived(endpoint, feed string) {
+	m.messages.Add(context.Background(), 1, metric.WithAttributes(attribute.String("endpoint", endpoint), attribute.String("feed", feed)))
+}
+
+// DecodeError implements sdk.Metrics
+func (m *Metrics) DecodeError(endpoint, feed string) {
+	m.decodeErrors.Add(context.Background(), 1, metric.WithAttributes(attribute.String("endpoint", endpoint), attribute.String("feed", feed)))
+}
+
+// CallbackDuration implements sdk.Metrics
+func (m *Metrics) CallbackDuration(feed string, duration time.Duration) {
+	m.callbackDuration.Record(context.Background(), duration.Seconds(), metric.WithAttributes(attribute.String("feed", feed)))
+}
+
+// QueueDepth implements sdk.Metrics
+func (m *Metrics) QueueDepth(feed string, depth int) {
+	m.queueDepth.Record(context.Background(), int64(depth), metric.WithAttributes(attribute.String("feed", feed)))
+}
+
+// NotificationDropped implements sdk.Metrics
+func (m *Metrics) NotificationDropped(feed string) {
+	m.dropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("feed", feed)))
+}
+
+// RequestDuration implements sdk.Metrics
+func (m *Metrics) RequestDuration(endpoint, method string, duration time.Duration, err error) {
+	m.requestDuration.Record(context.Background(), duration.Seconds(), metric.WithAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.String("method", method),
+		attribute.Bool("error", err != nil),
+	))
+}
+
+// Reconnect implements sdk.Metrics
+func (m *Metrics) Reconnect(endpoint string) {
+	m.reconnects.Add(context.Background(), 1, metric.WithAttributes(attribute.String("endpoint", endpoint)))
+}
+
+// Disconnected implements sdk.Metrics
+func (m *Metrics) Disconnected(endpoint string, duration time.Duration) {
+	m.disconnectedSeconds.Add(context.Background(), duration.Seconds(), metric.WithAttributes(attribute.String("endpoint", endpoint)))
+}
@@ -0,0 +1,41 @@
+package otelmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := New(provider.Meter("github.com/bloXroute-Labs/bloxroute-sdk-go"))
+	require.NoError(t, err)
+
+	m.MessageReceived("wss://api.blxrbdn.com/ws", "newTxs")
+	m.MessageReceived("wss://api.blxrbdn.com/ws", "newTxs")
+	m.NotificationDropped("newTxs")
+	m.Reconnect("wss://api.blxrbdn.com/ws")
+	m.RequestDuration("wss://api.blxrbdn.com/ws", "blxr_tx", time.Millisecond, nil)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	sums := make(map[string]int64)
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		if sum, ok := metric.Data.(metricdata.Sum[int64]); ok {
+			for _, point := range sum.DataPoints {
+				sums[metric.Name] += point.Value
+			}
+		}
+	}
+	require.Equal(t, int64(2), sums["bloxroute.sdk.messages_received"])
+	require.Equal(t, int64(1), sums["bloxroute.sdk.notifications_dropped"])
+	require.Equal(t, int64(1), sums["bloxroute.sdk.reconnects"])
+}
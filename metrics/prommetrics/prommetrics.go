@@ -0,0 +1,129 @@
+// Package prommetrics records the metrics of the SDK client with Prometheus
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	sdk "github.com/bloXroute-Labs/bloxroute-sdk-go"
+)
+
+var _ sdk.Metrics = (*Metrics)(nil)
+
+// Metrics implements sdk.Metrics with Prometheus collectors
+type Metrics struct {
+	messages            *prometheus.CounterVec
+	decodeErrors        *prometheus.CounterVec
+	callbackDuration    *prometheus.HistogramVec
+	queueDepth          *prometheus.HistogramVec
+	dropped             *prometheus.CounterVec
+	requestDuration     *prometheus.HistogramVec
+	reconnects          *prometheus.CounterVec
+	disconnectedSeconds *prometheus.CounterVec
+}
+
+// New creates the collectors, with names prefixed by namespace (e.g. "bloxroute_sdk"), and registers them with reg
+func New(reg prometheus.Registerer, namespace string) (*Metrics, error) {
+	m := &Metrics{
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_received_total",
+			Help:      "Number of feed messages received per endpoint and feed.",
+		}, []string{"endpoint", "feed"}),
+		decodeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "decode_errors_total",
+			Help:      "Number of feed messages which couldn't be decoded per endpoint and feed.",
+		}, []string{"endpoint", "feed"}),
+		callbackDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "callback_duration_seconds",
+			Help:      "Time the subscription callbacks took per notification.",
+			Buckets:   []float64{.00001, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"feed"}),
+		queueDepth: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "dispatch_queue_depth",
+			Help:      "Number of notifications waiting for the callback when a notification is queued.",
+			Buckets:   []float64{0, 1, 10, 50, 100, 250, 500, 1000, 5000},
+		}, []string{"feed"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_dropped_total",
+			Help:      "Number of notifications dropped because the dispatch queue of their subscription was full.",
+		}, []string{"feed"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Time the requests took per endpoint, method and result.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"endpoint", "method", "result"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconnects_total",
+			Help:      "Number of attempts to reconnect to an endpoint or to reopen one of its gRPC streams.",
+		}, []string{"endpoint"}),
+		disconnectedSeconds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "disconnected_seconds_total",
+			Help:      "Time spent disconnected from an endpoint.",
+		}, []string{"endpoint"}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.messages, m.decodeErrors, m.callbackDuration, m.queueDepth, m.dropped, m.requestDuration, m.reconnects, m.disconnectedSeconds,
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// MessageReceived implements sdk.Metrics
+func (m *Metrics) MessageReceived(endpoint, feed string) {
+	m.messages.WithLabelValues(endpoint, feed).Inc()
+}
+
+// DecodeError implements sdk.Metrics
+func (m *Metrics) DecodeError(endpoint, feed string) {
+	m.decodeErrors.WithLabelValues(endpoint, feed).Inc()
+}
+
+// CallbackDuration implements sdk.Metrics
+func (m *Metrics) CallbackDuration(feed string, duration time.Duration) {
+	m.callbackDuration.WithLabelValues(feed).Observe(duration.Seconds())
+}
+
+// QueueDepth implements sdk.Metrics
+func (m *Metrics) QueueDepth(feed string, depth int) {
+	m.queueDepth.WithLabelValues(feed).Observe(float64(depth))
+}
+
+// NotificationDropped implements sdk.Metrics
+func (m *Metrics) NotificationDropped(feed string) {
+	m.dropped.WithLabelValues(feed).Inc()
+}
+
+// RequestDuration implements sdk.Metrics
+func (m *Metrics) RequestDuration(endpoint, method string, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	m.requestDuration.WithLabelValues(endpoint, method, result).Observe(duration.Seconds())
+}
+
+// Reconnect implements sdk.Metrics
+func (m *Metrics) Reconnect(endpoint string) {
+	m.reconnects.WithLabelValues(endpoint).Inc()
+}
+
+// Disconnected implements sdk.Metrics
+func (m *Metrics) Disconnected(endpoint string, duration time.Duration) {
+	m.disconnectedSeconds.WithLabelValues(endpoint).Add(duration.Seconds())
+}
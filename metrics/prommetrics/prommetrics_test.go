@@ -0,0 +1,37 @@
+package prommetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg, "bloxroute_sdk")
+	require.NoError(t, err)
+
+	m.MessageReceived("wss://api.blxrbdn.com/ws", "newTxs")
+	m.MessageReceived("wss://api.blxrbdn.com/ws", "newTxs")
+	m.DecodeError("wss://api.blxrbdn.com/ws", "newTxs")
+	m.RequestDuration("wss://api.blxrbdn.com/ws", "blxr_tx", time.Millisecond, nil)
+	m.RequestDuration("wss://api.blxrbdn.com/ws", "blxr_tx", time.Millisecond, errors.New("failed"))
+	m.NotificationDropped("newTxs")
+	m.Reconnect("wss://api.blxrbdn.com/ws")
+	m.Disconnected("wss://api.blxrbdn.com/ws", 2*time.Second)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.messages.WithLabelValues("wss://api.blxrbdn.com/ws", "newTxs")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.decodeErrors.WithLabelValues("wss://api.blxrbdn.com/ws", "newTxs")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.dropped.WithLabelValues("newTxs")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.reconnects.WithLabelValues("wss://api.blxrbdn.com/ws")))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.disconnectedSeconds.WithLabelValues("wss://api.blxrbdn.com/ws")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.requestDuration))
+
+	// the collectors can only be registered once
+	_, err = New(reg, "bloxroute_sdk")
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testMetrics counts the calls of each method by its labels
+type testMetrics struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{counts: make(map[string]int)}
+}
+
+func (m *testMetrics) add(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.counts[key]++
+}
+
+func (m *testMetrics) count(key string) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.counts[key]
+}
+
+func (m *testMetrics) MessageReceived(endpoint, feed string) {
+	m.add("message:" + endpoint + ":" + feed)
+}
+
+func (m *testMetrics) DecodeError(endpoint, feed string) {
+	m.add("decode_error:" + endpoint + ":" + feed)
+}
+
+func (m *testMetrics) CallbackDuration(feed string, _ time.Duration) {
+	m.add("callback:" + feed)
+}
+
+func (m *testMetrics) QueueDepth(feed string, _ int) {
+	m.add("queue:" + feed)
+}
+
+func (m *testMetrics) NotificationDropped(feed string) {
+	m.add("dropped:" + feed)
+}
+
+func (m *testMetrics) RequestDuration(endpoint, method string, _ time.Duration, err error) {
+	if err != nil {
+		m.add("request_error:" + endpoint + ":" + method)
+		return
+	}
+	m.add("request:" + endpoint + ":" + method)
+}
+
+func (m *testMetrics) Reconnect(endpoint string) {
+	m.add("reconnect:" + endpoint)
+}
+
+func (m *testMetrics) Disconnected(endpoint string, _ time.Duration) {
+	m.add("disconnected:" + endpoint)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("ws", func(t *testing.T) {
+		metrics := newTestMetrics()
+		url := testEventsServer(t)
+
+		c, err := NewClient(context.Background(), &Config{
+			WSGatewayURL: url,
+			AuthHeader:   "auth",
+			Metrics:      metrics,
+		})
+		require.NoError(t, err)
+
+		_, err = c.SubscribeNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+		require.NoError(t, err)
+
+		// the server drops the first connection right after the subscription
+		require.Eventually(t, func() bool {
+			return metrics.count("disconnected:"+url) == 1 && c.State() == StateConnected
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 1, metrics.count("reconnect:"+url))
+
+		require.NoError(t, c.Close())
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		metrics := newTestMetrics()
+		url := testGRPCGateway(t, &testGatewayServer{})
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: url,
+			AuthHeader:     "auth",
+			Metrics:        metrics,
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		txs := make(chan *NewTxNotification, 10)
+		err = c.OnNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+			txs <- result
+		})
+		require.NoError(t, err)
+
+		// the first stream fails after one transaction and is reopened
+		for i := 1; i <= 2; i++ {
+			select {
+			case <-txs:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timeout waiting for tx %d", i)
+			}
+		}
+
+		feed := string(types.NewTxsFeed)
+		require.Equal(t, 2, metrics.count("message:"+url+":"+feed))
+		require.Equal(t, 0, metrics.count("decode_error:"+url+":"+feed))
+		require.GreaterOrEqual(t, metrics.count("reconnect:"+url), 1)
+		require.Eventually(t, func() bool { return metrics.count("callback:"+feed) == 2 }, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 2, metrics.count("queue:"+feed))
+
+		_, err = c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		require.Equal(t, 1, metrics.count("request:"+url+":"+string(jsonrpc.RPCTx)))
+	})
+}
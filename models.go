@@ -29,6 +29,9 @@ type NewTxNotification struct {
 	LocalRegion bool                         `json:"localRegion"`
 	Time        string                       `json:"time"`
 	RawTx       string                       `json:"rawTx"`
+
+	// Race is set when the feed is subscribed on several endpoints with Config.RedundantFeeds
+	Race *FeedRace `json:"-"`
 }
 
 // NewTxNotificationTxContents is the transaction contents object for new transactions
@@ -163,4 +166,52 @@ type OnBdnBlockNotification struct {
 	FutureValidatorInfo []FutureValidatorInfo   `json:"future_validator_info"`
 	Transactions        []OnNewBlockTransaction `json:"transactions"`
 	Withdrawals         []OnBlockWithdrawal     `json:"withdrawals"`
+
+	// Race is set when the feed is subscribed on several endpoints with Config.RedundantFeeds
+	Race *FeedRace `json:"-"`
+}
+
+// SendTxReply is the reply to a transaction sent with SendTx
+type SendTxReply struct {
+	TxHash string `json:"tx_hash"`
+}
+
+// SendBundleReply is the reply to a bundle submitted with SendEthBundle or SendBscBundle
+type SendBundleReply struct {
+	BundleHash string `json:"bundleHash"`
+}
+
+// SendTxBatchReply is the reply to a batch of transactions. The transactions are referred to
+// by their index in the batch
+type SendTxBatchReply struct {
+	TxHashes []BatchTxHash  `json:"tx_hashes"`
+	TxErrors []BatchTxError `json:"tx_errors"`
+}
+
+// BatchTxHash is the hash of an accepted transaction of a batch
+type BatchTxHash struct {
+	Index  int    `json:"idx"`
+	TxHash string `json:"tx_hash"`
+}
+
+// BatchTxError is the error of a rejected transaction of a batch
+type BatchTxError struct {
+	Index int    `json:"idx"`
+	Error string `json:"error"`
+}
+
+// QuotaUsage is the usage of the account's daily quota
+type QuotaUsage struct {
+	AccountID   string `json:"account_id"`
+	QuotaFilled uint64 `json:"quota_filled"`
+	QuotaLimit  uint64 `json:"quota_limit"`
+}
+
+// Remaining returns the part of the quota which is left
+func (q *QuotaUsage) Remaining() uint64 {
+	if q.QuotaFilled >= q.QuotaLimit {
+		return 0
+	}
+
+	return q.QuotaLimit - q.QuotaFilled
 }
@@ -2,30 +2,37 @@ package bloxroute_sdk_go
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-
-	"github.com/sourcegraph/jsonrpc2"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
 
 var (
-	ErrCloudAPIOnly = errors.New("OnTxStatus & MonitorTx are only supported on the cloud API")
+	ErrCloudAPIOnly = errors.New("OnTxStatus & MonitorTx are only supported on the cloud API over WebSocket")
 	ErrNoSubID      = errors.New("failed to find subscription for transaction status feed")
+	// ErrSeveralSubIDs is returned by MonitorTxs and StopMonitoringTx when several subscriptions to the
+	// transaction status feed are active and the params don't name the subscription
+	ErrSeveralSubIDs = errors.New("several subscriptions for transaction status feed, the subscription must be specified")
 )
 
 // OnTxStatus subscribes to a stream of transaction statuses
-func (c *Client) OnTxStatus(ctx context.Context, params OnTxStatusParams) error {
+func (c *Client) OnTxStatus(ctx context.Context, params OnTxStatusParams, opts ...CallOption) error {
+	_, err := c.SubscribeTxStatus(ctx, params, opts...)
+	return err
+}
+
+// SubscribeTxStatus subscribes to a stream of transaction statuses and returns the handle of the subscription
+func (c *Client) SubscribeTxStatus(ctx context.Context, params OnTxStatusParams, opts ...CallOption) (*Subscription, error) {
 	if c.handler.Type() != handlerSourceTypeCloudAPIWS {
-		return ErrCloudAPIOnly
+		return nil, ErrCloudAPIOnly
 	}
 
 	// return an error if there is no callback
 	if params.Callback == nil {
-		return fmt.Errorf("callback is required")
+		return nil, fmt.Errorf("callback is required")
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
@@ -36,52 +43,63 @@ func (c *Client) OnTxStatus(ctx context.Context, params OnTxStatusParams) error
 		params.Callback(ctx, err, result.(*OnTxStatusNotification))
 	}
 
-	_, err := subscribeTransactionStatus(ctx, c.handler, wrap)
+	sub, err := subscribeTransactionStatus(ctx, c, wrap, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if params.Transactions != nil {
-		return c.MonitorTxs(ctx, &MonitorTxsParams{
+		err = c.MonitorTxs(ctx, &MonitorTxsParams{
 			Transactions: params.Transactions,
-		})
+			Subscription: sub,
+		}, opts...)
+		if err != nil {
+			return nil, errors.Join(err, sub.Unsubscribe())
+		}
 	}
 
-	return nil
+	return sub, nil
 }
 
-// MonitorTxs monitors the status of transactions
-func (c *Client) MonitorTxs(ctx context.Context, params *MonitorTxsParams) error {
-	if c.handler.Type() != handlerSourceTypeCloudAPIWS {
+// OnTxStatusChan subscribes to a stream of transaction statuses and returns a channel of its notifications.
+// The transactions, if any, are monitored right away. The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnTxStatusChan(ctx context.Context, transactions []string, opts ...CallOption) (<-chan Notification[*OnTxStatusNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnTxStatusNotification]) (*Subscription, error) {
+		return c.SubscribeTxStatus(ctx, OnTxStatusParams{Callback: callback, Transactions: transactions}, opts...)
+	})
+}
+
+// OnTxStatusSeq returns an iterator over a stream of transaction statuses.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnTxStatusSeq(ctx context.Context, transactions []string, opts ...CallOption) iter.Seq2[*OnTxStatusNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnTxStatusNotification]) (*Subscription, error) {
+		return c.SubscribeTxStatus(ctx, OnTxStatusParams{Callback: callback, Transactions: transactions}, opts...)
+	})
+}
+
+// MonitorTxs monitors the status of transactions, which are reported to the subscription of the params,
+// or to the only transaction status subscription
+func (c *Client) MonitorTxs(ctx context.Context, params *MonitorTxsParams, opts ...CallOption) error {
+	// the active handler is read once, as a failover may replace it with one of another type
+	handler, ok := activeHandler(c.handler).(*wsHandler)
+	if !ok || handler.Type() != handlerSourceTypeCloudAPIWS {
 		return ErrCloudAPIOnly
 	}
 
-	handler := c.handler.(*wsHandler)
-
-	feed, ok := handler.feeds[types.TransactionStatusFeed]
-	if !ok {
+	_, subscriptionId, err := handler.feedServerID(types.TransactionStatusFeed, params.Subscription.subscriptionID())
+	if errors.Is(err, ErrNoSubID) {
 		return fmt.Errorf("please subscribe to a transaction status feed with OnTxStatus before calling MonitorTxs")
 	}
-
-	subscriptionId := feed.subscriptionID
+	if err != nil {
+		return err
+	}
 
 	monitorTxsParams := &monitorTxsParams{
 		Transactions:   params.Transactions,
 		SubscriptionID: subscriptionId,
 	}
 
-	raw, err := json.Marshal(monitorTxsParams)
-	if err != nil {
-		return fmt.Errorf("failed to marshal params: %w", err)
-	}
-
-	subRequest := &jsonrpc2.Request{
-		ID:     randomID(),
-		Method: string(jsonrpc.RPCStartMonitoringTx),
-		Params: (*json.RawMessage)(&raw),
-	}
-
-	_, err = handler.request(ctx, subRequest)
+	_, err = c.request(ctx, handler, jsonrpc.RPCStartMonitoringTx, monitorTxsParams, opts)
 	if err != nil {
 		return fmt.Errorf("failed to start monitoring transactions: %w", err)
 	}
@@ -89,30 +107,12 @@ func (c *Client) MonitorTxs(ctx context.Context, params *MonitorTxsParams) error
 	return nil
 }
 
-func subscribeTransactionStatus(ctx context.Context, h handler, callback CallbackFunc[any]) (string, error) {
-	if h.Type() != handlerSourceTypeCloudAPIWS {
-		return "", ErrCloudAPIOnly
-	}
-
-	hh := h.(*wsHandler)
-
-	raw, err := json.Marshal([]interface{}{types.TransactionStatusFeed, map[string]any{"include": []string{"tx_hash", "status"}}})
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal params: %w", err)
-	}
-
-	req := &jsonrpc2.Request{
-		ID:     randomID(),
-		Method: string(jsonrpc.RPCSubscribe),
-		Params: (*json.RawMessage)(&raw),
-	}
-
-	resChan, err := hh.subscribe(ctx, types.TransactionStatusFeed, req)
-	if err != nil {
-		return "", err
+func subscribeTransactionStatus(ctx context.Context, c *Client, callback CallbackFunc[any], opts []CallOption) (*Subscription, error) {
+	if c.handler.Type() != handlerSourceTypeCloudAPIWS {
+		return nil, ErrCloudAPIOnly
 	}
 
-	return hh.waitSubscriptionResponse(ctx, resChan, types.TransactionStatusFeed, req, callback)
+	return c.subscribe(ctx, types.TransactionStatusFeed, map[string]any{"include": []string{"tx_hash", "status"}}, callback, opts)
 }
 
 // OnTxStatusParams allow you to include a parameters and a callback function
@@ -129,6 +129,10 @@ type OnTxStatusParams struct {
 type MonitorTxsParams struct {
 	// Raw transaction bytes without 0x prefix.
 	Transactions []string `json:"transactions"`
+
+	// [Optional] Subscription is the transaction status subscription, returned by SubscribeTxStatus,
+	// to report the statuses to. Required when several transaction status subscriptions are active.
+	Subscription *Subscription `json:"-"`
 }
 
 type monitorTxsParams struct {
@@ -142,6 +146,10 @@ type StopMonitoringTxParams struct {
 	Transactions []string `json:"transactions"`
 	// Raw transaction bytes without 0x prefix.
 	TransactionHash []string `json:"transaction_hash"`
+
+	// [Optional] Subscription is the transaction status subscription, returned by SubscribeTxStatus,
+	// which monitors the transactions. Required when several transaction status subscriptions are active.
+	Subscription *Subscription `json:"-"`
 }
 
 type stopMonitoringTxParams struct {
@@ -150,37 +158,32 @@ type stopMonitoringTxParams struct {
 	SubscriptionID  string   `json:"subscription_id"`
 }
 
-// StopMonitoringTx stops monitoring the status of transactions specified
-func (c *Client) StopMonitoringTx(ctx context.Context, params *StopMonitoringTxParams) error {
-	if c.handler.Type() != handlerSourceTypeCloudAPIWS {
+// StopMonitoringTx stops monitoring the status of transactions specified, and ends the transaction status
+// subscription of the params, or the only one. The other transaction status subscriptions are kept.
+func (c *Client) StopMonitoringTx(ctx context.Context, params *StopMonitoringTxParams, opts ...CallOption) error {
+	handler, ok := activeHandler(c.handler).(*wsHandler)
+	if !ok || handler.Type() != handlerSourceTypeCloudAPIWS {
 		return ErrCloudAPIOnly
 	}
 
-	handler := c.handler.(*wsHandler)
-
-	feed, ok := handler.feeds[types.TransactionStatusFeed]
-	if !ok {
-		return ErrNoSubID
-	}
-
-	_, ok = handler.subscriptions[feed.subscriptionID]
-	if !ok {
-		return ErrNoSubID
+	id, subscriptionID, err := handler.feedServerID(types.TransactionStatusFeed, params.Subscription.subscriptionID())
+	if err != nil {
+		return err
 	}
 
 	// Included Transactions; seems the cloud API requires it despite the docs
 	stopMonitorTxsParams := &stopMonitoringTxParams{
 		Transactions:    params.Transactions,
 		TransactionHash: params.TransactionHash,
-		SubscriptionID:  feed.subscriptionID,
+		SubscriptionID:  subscriptionID,
 	}
 
-	_, err := handler.Request(ctx, jsonrpc.RPCStopMonitoringTx, stopMonitorTxsParams)
+	_, err = c.request(ctx, handler, jsonrpc.RPCStopMonitoringTx, stopMonitorTxsParams, opts)
 	if err != nil {
 		return fmt.Errorf("failed to stop monitoring transactions: %w", err)
 	}
 
-	err = handler.UnsubscribeRetry(types.TransactionStatusFeed)
+	err = c.handler.Unsubscribe(id)
 	if err != nil {
 		return fmt.Errorf("failed to unsubscribe from transaction status feed: %w", err)
 	}
@@ -2,6 +2,7 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -15,7 +16,14 @@ type NewBlockParams struct {
 }
 
 // OnNewBlock subscribes to a stream of all new blocks as they are propagated in the BDN.
-func (c *Client) OnNewBlock(ctx context.Context, params *NewBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification]) error {
+func (c *Client) OnNewBlock(ctx context.Context, params *NewBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification], opts ...CallOption) error {
+	_, err := c.SubscribeNewBlock(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribeNewBlock subscribes to the new blocks feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribeNewBlock(ctx context.Context, params *NewBlockParams, callbackFunc CallbackFunc[*OnBdnBlockNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
 		params = &NewBlockParams{}
 	}
@@ -25,10 +33,30 @@ func (c *Client) OnNewBlock(ctx context.Context, params *NewBlockParams, callbac
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
+		if err != nil {
+			callbackFunc(ctx, err, nil)
+			return
+		}
 		callbackFunc(ctx, err, result.(*OnBdnBlockNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.NewBlocksFeed, params, wrap)
+	return c.subscribe(ctx, types.NewBlocksFeed, params, wrap, opts)
+}
+
+// OnNewBlockChan subscribes to the new blocks feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnNewBlockChan(ctx context.Context, params *NewBlockParams, opts ...CallOption) (<-chan Notification[*OnBdnBlockNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBdnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeNewBlock(ctx, params, callback, opts...)
+	})
+}
+
+// OnNewBlockSeq returns an iterator over the notifications of the new blocks feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnNewBlockSeq(ctx context.Context, params *NewBlockParams, opts ...CallOption) iter.Seq2[*OnBdnBlockNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnBdnBlockNotification]) (*Subscription, error) {
+		return c.SubscribeNewBlock(ctx, params, callback, opts...)
+	})
 }
 
 func (c *Client) UnsubscribeFromOnNewBlock() error {
@@ -11,6 +11,7 @@ import (
 func TestOnNewBlock(t *testing.T) {
 	t.Run("ws_cloud_api", testOnNewBlock(wsCloudApiUrl))
 	t.Run("ws_gateway", testOnNewBlock(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testOnNewBlock(grpcCloudApiUrl))
 	t.Run("grpc_gateway", testOnNewBlock(grpcGatewayUrl))
 }
 
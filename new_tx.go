@@ -2,6 +2,7 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -24,7 +25,7 @@ const (
 type NewTxParams struct {
 	// Include is the list of fields to include in the response.
 	// The values of these fields depend on the feed type.
-	// Optional (defaults to ["tx_hash"] for WS and ["raw_tx"] for GRPC)
+	// Optional (defaults to ["tx_hash"] for WS, ["raw_tx"] for GRPC and both with Config.RedundantFeeds)
 	Include []string `json:"include"`
 
 	// Duplicates indicates whether to include transactions already published in the feed
@@ -45,18 +46,21 @@ type NewTxParams struct {
 }
 
 // OnNewTx subscribes to new transactions feed
-func (c *Client) OnNewTx(ctx context.Context, params *NewTxParams, callbackFunc CallbackFunc[*NewTxNotification]) error {
+func (c *Client) OnNewTx(ctx context.Context, params *NewTxParams, callbackFunc CallbackFunc[*NewTxNotification], opts ...CallOption) error {
+	_, err := c.SubscribeNewTx(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribeNewTx subscribes to the new transactions feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribeNewTx(ctx context.Context, params *NewTxParams, callbackFunc CallbackFunc[*NewTxNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
 		params = &NewTxParams{}
 	}
 
 	// add at least tx_hash to the include list
 	if len(params.Include) == 0 {
-		if c.handler.Type() != handlerSourceTypeGatewayGRPC {
-			params.Include = []string{"tx_hash"}
-		} else {
-			params.Include = []string{"raw_tx"}
-		}
+		params.Include = c.defaultTxInclude()
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
@@ -67,10 +71,40 @@ func (c *Client) OnNewTx(ctx context.Context, params *NewTxParams, callbackFunc
 		callbackFunc(ctx, err, result.(*NewTxNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.NewTxsFeed, params, wrap)
+	return c.subscribe(ctx, types.NewTxsFeed, params, wrap, opts)
+}
+
+// OnNewTxChan subscribes to the new transactions feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnNewTxChan(ctx context.Context, params *NewTxParams, opts ...CallOption) (<-chan Notification[*NewTxNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*NewTxNotification]) (*Subscription, error) {
+		return c.SubscribeNewTx(ctx, params, callback, opts...)
+	})
+}
+
+// OnNewTxSeq returns an iterator over the notifications of the new transactions feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnNewTxSeq(ctx context.Context, params *NewTxParams, opts ...CallOption) iter.Seq2[*NewTxNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*NewTxNotification]) (*Subscription, error) {
+		return c.SubscribeNewTx(ctx, params, callback, opts...)
+	})
+}
+
+// defaultTxInclude returns the fields included in transaction feeds when none are requested
+func (c *Client) defaultTxInclude() []string {
+	if h, ok := c.handler.(*multiHandler); ok && h.config.RedundantFeeds {
+		// transactions are matched by tx_hash from WS endpoints and by raw_tx from gRPC endpoints
+		return []string{"tx_hash", "raw_tx"}
+	}
+
+	if c.handler.Type().isGRPC() {
+		return []string{"raw_tx"}
+	}
+
+	return []string{"tx_hash"}
 }
 
-// UnsubscribeFromNewTxs unsubscribes from new transactions feed
+// UnsubscribeFromNewTxs ends all subscriptions to new transactions feed
 func (c *Client) UnsubscribeFromNewTxs() error {
 	return c.handler.UnsubscribeRetry(types.NewTxsFeed)
 }
@@ -11,6 +11,7 @@ import (
 func TestOnNewTx(t *testing.T) {
 	t.Run("ws_cloud_api", testOnNewTx(wsCloudApiUrl))
 	t.Run("ws_gateway", testOnNewTx(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testOnNewTx(grpcCloudApiUrl))
 	t.Run("grpc_gateway", testOnNewTx(grpcGatewayUrl))
 }
 
@@ -50,3 +51,109 @@ func testOnNewTx(url testURL) func(t *testing.T) {
 		require.NoError(t, c.Close())
 	}
 }
+
+func TestOnNewTxSeq(t *testing.T) {
+	t.Run("ws_cloud_api", testOnNewTxSeq(wsCloudApiUrl))
+	t.Run("grpc_gateway", testOnNewTxSeq(grpcGatewayUrl))
+}
+
+func testOnNewTxSeq(url testURL) func(t *testing.T) {
+	return func(t *testing.T) {
+		config := testConfig(t, url)
+
+		c, err := NewClient(context.Background(), config)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var received int
+		for result, err := range c.OnNewTxSeq(ctx, &NewTxParams{Include: []string{"raw_tx"}}) {
+			require.NoError(t, err)
+			require.NotNilf(t, result, "result is nil")
+			require.NotEmptyf(t, result.RawTx, "raw tx is empty")
+
+			received++
+			if received == 2 {
+				break
+			}
+		}
+		require.Equal(t, 2, received, "timeout waiting for new txs")
+
+		// the loop has ended the subscription, so the feed can be subscribed to again
+		ch, err := c.OnNewTxChan(ctx, &NewTxParams{Include: []string{"raw_tx"}})
+		require.NoError(t, err)
+
+		select {
+		case n := <-ch:
+			require.NoError(t, n.Err)
+		case <-ctx.Done():
+			require.Fail(t, "timeout waiting for new tx")
+		}
+
+		require.NoError(t, c.Close())
+	}
+}
+
+func TestSubscribeNewTx(t *testing.T) {
+	t.Run("ws_cloud_api", testSubscribeNewTx(wsCloudApiUrl))
+	t.Run("ws_gateway", testSubscribeNewTx(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testSubscribeNewTx(grpcCloudApiUrl))
+	t.Run("grpc_gateway", testSubscribeNewTx(grpcGatewayUrl))
+}
+
+func testSubscribeNewTx(url testURL) func(t *testing.T) {
+	return func(t *testing.T) {
+		config := testConfig(t, url)
+
+		c, err := NewClient(context.Background(), config)
+		require.NoError(t, err)
+
+		// two subscriptions to the same feed with different params
+		all := make(chan *NewTxNotification, 100)
+		subAll, err := c.SubscribeNewTx(context.Background(), &NewTxParams{Include: []string{"raw_tx"}}, func(ctx context.Context, err error, result *NewTxNotification) {
+			require.NoError(t, err)
+			select {
+			case all <- result:
+			default:
+			}
+		})
+		require.NoError(t, err)
+
+		filtered := make(chan *NewTxNotification, 100)
+		subFiltered, err := c.SubscribeNewTx(context.Background(), &NewTxParams{Include: []string{"raw_tx"}, Filters: "{value} > 0"}, func(ctx context.Context, err error, result *NewTxNotification) {
+			require.NoError(t, err)
+			select {
+			case filtered <- result:
+			default:
+			}
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, subAll.ID(), subFiltered.ID())
+
+		for _, ch := range []chan *NewTxNotification{all, filtered} {
+			select {
+			case result := <-ch:
+				require.NotEmptyf(t, result.RawTx, "raw tx is empty")
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "timeout waiting for new tx")
+			}
+		}
+
+		// ending one subscription doesn't affect the other
+		require.NoError(t, subFiltered.Unsubscribe())
+
+		for len(all) > 0 {
+			<-all
+		}
+
+		select {
+		case <-all:
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "timeout waiting for new tx after unsubscribing the other subscription")
+		}
+
+		require.NoError(t, c.UnsubscribeFromNewTxs())
+		require.NoError(t, c.Close())
+	}
+}
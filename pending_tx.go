@@ -2,6 +2,7 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -10,7 +11,7 @@ import (
 type PendingTxParams struct {
 	// Include is the list of fields to include in the response.
 	// The values of these fields depend on the feed type.
-	// Optional (defaults to ["tx_hash"])
+	// Optional (defaults to ["tx_hash"] for WS, ["raw_tx"] for GRPC and both with Config.RedundantFeeds)
 	Include []string `json:"include"`
 
 	// Duplicates indicates whether to include transactions already published in the feed
@@ -31,14 +32,21 @@ type PendingTxParams struct {
 }
 
 // OnPendingTx subscribes to types.PendingTxsFeed feed
-func (c *Client) OnPendingTx(ctx context.Context, params *PendingTxParams, callbackFunc CallbackFunc[*NewTxNotification]) error {
+func (c *Client) OnPendingTx(ctx context.Context, params *PendingTxParams, callbackFunc CallbackFunc[*NewTxNotification], opts ...CallOption) error {
+	_, err := c.SubscribePendingTx(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribePendingTx subscribes to the pending transactions feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribePendingTx(ctx context.Context, params *PendingTxParams, callbackFunc CallbackFunc[*NewTxNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
 		params = &PendingTxParams{}
 	}
 
 	// add at least tx_hash to the include list
 	if len(params.Include) == 0 {
-		params.Include = []string{"tx_hash"}
+		params.Include = c.defaultTxInclude()
 	}
 
 	wrap := func(ctx context.Context, err error, result any) {
@@ -49,10 +57,26 @@ func (c *Client) OnPendingTx(ctx context.Context, params *PendingTxParams, callb
 		callbackFunc(ctx, err, result.(*NewTxNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.PendingTxsFeed, params, wrap)
+	return c.subscribe(ctx, types.PendingTxsFeed, params, wrap, opts)
+}
+
+// OnPendingTxChan subscribes to the pending transactions feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnPendingTxChan(ctx context.Context, params *PendingTxParams, opts ...CallOption) (<-chan Notification[*NewTxNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*NewTxNotification]) (*Subscription, error) {
+		return c.SubscribePendingTx(ctx, params, callback, opts...)
+	})
+}
+
+// OnPendingTxSeq returns an iterator over the notifications of the pending transactions feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnPendingTxSeq(ctx context.Context, params *PendingTxParams, opts ...CallOption) iter.Seq2[*NewTxNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*NewTxNotification]) (*Subscription, error) {
+		return c.SubscribePendingTx(ctx, params, callback, opts...)
+	})
 }
 
-// UnsubscribeFromPendingTxs unsubscribes from types.PendingTxsFeed feed
+// UnsubscribeFromPendingTxs ends all subscriptions to types.PendingTxsFeed feed
 func (c *Client) UnsubscribeFromPendingTxs() error {
 	return c.handler.UnsubscribeRetry(types.PendingTxsFeed)
 }
@@ -11,6 +11,7 @@ import (
 func TestOnPendingTx(t *testing.T) {
 	t.Run("ws_cloud_api", testOnPendingTx(wsCloudApiUrl))
 	t.Run("ws_gateway", testOnPendingTx(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testOnPendingTx(grpcCloudApiUrl))
 	t.Run("grpc_gateway", testOnPendingTx(grpcGatewayUrl))
 }
 
@@ -0,0 +1,26 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+)
+
+// QuotaUsage gets the usage of the account's daily quota, to slow down before the cloud API
+// starts rejecting requests. Only available over WebSocket.
+func (c *Client) QuotaUsage(ctx context.Context, opts ...CallOption) (*QuotaUsage, error) {
+	res, err := c.request(ctx, c.handler, jsonrpc.RPCQuotaUsage, struct{}{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage QuotaUsage
+	err = json.Unmarshal(*res, &usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quota usage: %w", err)
+	}
+
+	return &usage, nil
+}
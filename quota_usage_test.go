@@ -0,0 +1,31 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaUsage(t *testing.T) {
+	t.Run("ws_cloud_api", testQuotaUsage(wsCloudApiUrl))
+	if testLive(wsCloudApiUrl) {
+		time.Sleep(5 * time.Second) // give the ws conn time to close
+	}
+}
+
+func testQuotaUsage(url testURL) func(t *testing.T) {
+	return func(t *testing.T) {
+		config := testConfig(t, url)
+
+		c, err := NewClient(context.Background(), config)
+		require.NoError(t, err)
+
+		usage, err := c.QuotaUsage(context.Background())
+
+		require.NoError(t, err)
+		require.NotEmpty(t, usage.AccountID)
+		require.NoError(t, c.Close())
+	}
+}
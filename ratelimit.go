@@ -0,0 +1,180 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+)
+
+// ErrRateLimited is returned for a call rejected by the rate limits of the client, or by the endpoint
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimit is a token bucket which limits the calls of a class of methods
+type RateLimit struct {
+	// Rate is the number of calls per second the bucket is refilled with
+	Rate float64
+
+	// Burst is the number of calls which can be made at once
+	// Optional (default: 1)
+	Burst int
+}
+
+// RateLimitMode decides what happens to a call when the bucket of its class is empty
+type RateLimitMode int
+
+// RateLimitMode enumeration
+const (
+	// RateLimitWait makes the call wait for a token until its context is done
+	RateLimitWait RateLimitMode = iota
+	// RateLimitReject fails the call with ErrRateLimited right away
+	RateLimitReject
+)
+
+// RateLimits are the token buckets of the classes of methods. A class without a bucket isn't limited.
+// A call takes a single token, even if it is retried or fanned out to several endpoints.
+type RateLimits struct {
+	// Sends limits SendTx, SendTxBatch and SendPrivateTx, and their FanOut variants
+	Sends *RateLimit
+
+	// Bundles limits SendEthBundle and SendBscBundle, and their FanOut variants
+	Bundles *RateLimit
+
+	// Subscriptions limits the subscriptions to feeds, including the transaction status
+	Subscriptions *RateLimit
+
+	// Mode decides what happens to a call when the bucket of its class is empty.
+	// Calls made WithPriority(PriorityHigh) take the tokens ahead of the waiting normal priority calls.
+	// Optional (default: RateLimitWait)
+	Mode RateLimitMode
+}
+
+// rateLimiter holds the token buckets of the classes of methods, nil for a class which isn't limited
+type rateLimiter struct {
+	sends         *tokenBucket
+	bundles       *tokenBucket
+	subscriptions *tokenBucket
+	reject        bool
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		sends:         newTokenBucket(limits.Sends),
+		bundles:       newTokenBucket(limits.Bundles),
+		subscriptions: newTokenBucket(limits.Subscriptions),
+		reject:        limits.Mode == RateLimitReject,
+	}
+}
+
+// request takes a token for a request of the given method, if its class is limited
+func (l *rateLimiter) request(ctx context.Context, method jsonrpc.RPCRequestType) error {
+	if l == nil {
+		return nil
+	}
+
+	switch method {
+	case jsonrpc.RPCTx, jsonrpc.RPCBatchTx, jsonrpc.RPCPrivateTx, RPCBSCPrivateTx, RPCPolygonPrivateTx:
+		return l.sends.take(ctx, callOptionsFromContext(ctx).priority, l.reject)
+	case jsonrpc.RPCBundleSubmission:
+		return l.bundles.take(ctx, callOptionsFromContext(ctx).priority, l.reject)
+	default:
+		return nil
+	}
+}
+
+// subscribe takes a token for a subscription
+func (l *rateLimiter) subscribe(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	return l.subscriptions.take(ctx, callOptionsFromContext(ctx).priority, l.reject)
+}
+
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	lock  *sync.Mutex
+	// tokens is the number of tokens at the time last
+	tokens float64
+	last   time.Time
+	// highWaiting is the number of high priority calls waiting for a token,
+	// which the normal priority calls leave the tokens to
+	highWaiting int
+}
+
+// newTokenBucket returns a full bucket, or nil if the limit doesn't limit anything
+func newTokenBucket(limit *RateLimit) *tokenBucket {
+	if limit == nil || limit.Rate <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		lock:   &sync.Mutex{},
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// take takes a token, waiting for it until ctx is done unless reject is set
+func (b *tokenBucket) take(ctx context.Context, priority Priority, reject bool) error {
+	if b == nil {
+		return nil
+	}
+
+	high := priority == PriorityHigh
+	var waiting bool
+	for {
+		b.lock.Lock()
+
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+
+		if b.tokens >= 1 && (high || b.highWaiting == 0) {
+			b.tokens--
+			if waiting {
+				b.highWaiting--
+			}
+			b.lock.Unlock()
+			return nil
+		}
+
+		if reject {
+			b.lock.Unlock()
+			return ErrRateLimited
+		}
+
+		if high && !waiting {
+			b.highWaiting++
+			waiting = true
+		}
+
+		// a normal priority call which only waits for the high priority ones checks again soon
+		delay := max(time.Millisecond, time.Duration((1-b.tokens)/b.rate*float64(time.Second)))
+		b.lock.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if waiting {
+				b.lock.Lock()
+				b.highWaiting--
+				b.lock.Unlock()
+			}
+			return fmt.Errorf("%w: %w", ErrRateLimited, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
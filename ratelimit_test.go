@@ -0,0 +1,88 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("reject", func(t *testing.T) {
+		c := &Client{
+			handler: &testRequestHandler{result: `{"tx_hash":"0x1"}`},
+			config:  &Config{},
+			limiter: newRateLimiter(RateLimits{Sends: &RateLimit{Rate: 0.001, Burst: 2}, Mode: RateLimitReject}),
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+			require.NoError(t, err)
+		}
+
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+		require.ErrorIs(t, err, ErrRateLimited)
+
+		// the other classes have their own buckets, or none
+		_, err = c.GetBscBundlePrice(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		c := &Client{
+			handler: &testRequestHandler{result: `{"tx_hash":"0x1"}`},
+			config:  &Config{},
+			limiter: newRateLimiter(RateLimits{Sends: &RateLimit{Rate: 20}}),
+		}
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"})
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+		// the call gives up waiting when its timeout elapses
+		_, err := c.SendTx(context.Background(), &SendTxParams{Transaction: "f86b"}, WithTimeout(time.Millisecond))
+		require.ErrorIs(t, err, ErrRateLimited)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		bucket := newTokenBucket(&RateLimit{Rate: 20})
+		require.NoError(t, bucket.take(context.Background(), PriorityNormal, false))
+
+		order := make(chan Priority, 2)
+		go func() {
+			require.NoError(t, bucket.take(context.Background(), PriorityNormal, false))
+			order <- PriorityNormal
+		}()
+		time.Sleep(5 * time.Millisecond)
+		go func() {
+			require.NoError(t, bucket.take(context.Background(), PriorityHigh, false))
+			order <- PriorityHigh
+		}()
+
+		require.Equal(t, PriorityHigh, <-order)
+		require.Equal(t, PriorityNormal, <-order)
+	})
+
+	t.Run("endpoint_error", func(t *testing.T) {
+		require.ErrorIs(t, &RPCError{Code: jsonrpc2.CodeInternalError, Message: "Rate limit reached, try again later"}, ErrRateLimited)
+		require.NotErrorIs(t, &RPCError{Code: jsonrpc2.CodeInvalidParams, Message: "invalid transaction"}, ErrRateLimited)
+	})
+}
+
+func TestQuotaUsageReply(t *testing.T) {
+	c := &Client{
+		handler: &testRequestHandler{result: `{"account_id":"a1","quota_filled":250,"quota_limit":1000}`},
+		config:  &Config{},
+	}
+
+	usage, err := c.QuotaUsage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &QuotaUsage{AccountID: "a1", QuotaFilled: 250, QuotaLimit: 1000}, usage)
+	require.EqualValues(t, 750, usage.Remaining())
+}
@@ -0,0 +1,169 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const defaultRedundantFeedsCacheSize = 10000
+
+// FeedRace describes which endpoint delivered a redundant feed notification first
+type FeedRace struct {
+	// Source is the endpoint which delivered the notification first
+	Source string
+
+	// ReceivedAt is when the notification was received from Source
+	ReceivedAt time.Time
+
+	leadMicros atomic.Int64
+}
+
+// LeadMicros returns by how many microseconds Source was ahead of the first other endpoint
+// which delivered the same notification. The notification is passed to the callback as soon as it
+// arrives from Source, so the lead is not known yet while the callback runs: it is 0 until the
+// notification arrives from another endpoint, and is only worth reading if the FeedRace is kept.
+func (r *FeedRace) LeadMicros() int64 {
+	return r.leadMicros.Load()
+}
+
+// redundantFeed reports whether notifications of the feed can be matched across endpoints
+func redundantFeed(f types.FeedType) bool {
+	switch f {
+	case types.NewTxsFeed, types.PendingTxsFeed, types.NewBlocksFeed, types.BDNBlocksFeed:
+		return true
+	default:
+		return false
+	}
+}
+
+// raceFilter merges the notifications of a feed received from several endpoints,
+// passing each transaction or block to the callback only once. The callback is called
+// without holding the lock, so the endpoints may call it concurrently.
+type raceFilter struct {
+	callback CallbackFunc[any]
+	races    map[string]*FeedRace
+	// keys holds the keys of races in insertion order, so the oldest can be evicted
+	keys []string
+	next int
+	lock *sync.Mutex
+}
+
+func newRaceFilter(size int, callback CallbackFunc[any]) *raceFilter {
+	return &raceFilter{
+		callback: callback,
+		races:    make(map[string]*FeedRace, size),
+		keys:     make([]string, size),
+		lock:     &sync.Mutex{},
+	}
+}
+
+// wrap returns the callback for the subscription made on the given endpoint
+func (r *raceFilter) wrap(source string) CallbackFunc[any] {
+	return func(ctx context.Context, err error, result any) {
+		now := time.Now()
+
+		if err != nil {
+			r.callback(ctx, err, result)
+			return
+		}
+
+		key := notificationKey(result)
+		if key == "" {
+			// nothing to match on, so just pass it through
+			r.callback(ctx, nil, result)
+			return
+		}
+
+		race, first := r.race(key, source, now)
+		if !first {
+			return
+		}
+
+		switch notification := result.(type) {
+		case *NewTxNotification:
+			notification.Race = race
+		case *OnBdnBlockNotification:
+			notification.Race = race
+		}
+
+		r.callback(ctx, nil, result)
+	}
+}
+
+// race returns the race of the notification with the key received from the source, and whether
+// the source is the first to deliver it. A later delivery from another source records the lead.
+func (r *raceFilter) race(key, source string, now time.Time) (*FeedRace, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	race, ok := r.races[key]
+	if ok {
+		if race.Source != source {
+			race.leadMicros.CompareAndSwap(0, now.Sub(race.ReceivedAt).Microseconds())
+		}
+
+		return race, false
+	}
+
+	race = &FeedRace{Source: source, ReceivedAt: now}
+	r.add(key, race)
+
+	return race, true
+}
+
+// add stores the race and evicts the oldest one when the filter is full
+func (r *raceFilter) add(key string, race *FeedRace) {
+	if oldest := r.keys[r.next]; oldest != "" {
+		delete(r.races, oldest)
+	}
+
+	r.keys[r.next] = key
+	r.next = (r.next + 1) % len(r.keys)
+	r.races[key] = race
+}
+
+// notificationKey returns the transaction or block hash of the notification
+func notificationKey(result any) string {
+	switch notification := result.(type) {
+	case *NewTxNotification:
+		if notification == nil {
+			return ""
+		}
+		if notification.TxHash != "" {
+			return strings.ToLower(notification.TxHash)
+		}
+		if notification.RawTx == "" {
+			return ""
+		}
+
+		// raw transactions are hex-encoded in WS notifications and binary in gRPC notifications
+		rawTx := []byte(notification.RawTx)
+		if strings.HasPrefix(notification.RawTx, "0x") {
+			rawTx = common.FromHex(notification.RawTx)
+		}
+
+		var tx ethtypes.Transaction
+		if err := tx.UnmarshalBinary(rawTx); err == nil {
+			return tx.Hash().Hex()
+		}
+
+		return crypto.Keccak256Hash(rawTx).Hex()
+	case *OnBdnBlockNotification:
+		if notification == nil {
+			return ""
+		}
+
+		return strings.ToLower(notification.Hash)
+	default:
+		return ""
+	}
+}
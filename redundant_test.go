@@ -0,0 +1,104 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaceFilter(t *testing.T) {
+	var delivered []any
+	filter := newRaceFilter(2, func(ctx context.Context, err error, result any) {
+		delivered = append(delivered, result)
+	})
+
+	fast := filter.wrap("fast")
+	slow := filter.wrap("slow")
+
+	tx := &NewTxNotification{TxHash: "0xAB"}
+	fast(context.Background(), nil, tx)
+	time.Sleep(time.Millisecond)
+	slow(context.Background(), nil, &NewTxNotification{TxHash: "0xab"})
+
+	require.Len(t, delivered, 1)
+	require.Equal(t, "fast", tx.Race.Source)
+	require.Positive(t, tx.Race.LeadMicros())
+
+	block := &OnBdnBlockNotification{Hash: "0x01"}
+	slow(context.Background(), nil, block)
+	fast(context.Background(), nil, &OnBdnBlockNotification{Hash: "0x01"})
+
+	require.Len(t, delivered, 2)
+	require.Equal(t, "slow", block.Race.Source)
+
+	// the filter remembers only the last two hashes, so the first one is delivered again
+	fast(context.Background(), nil, &NewTxNotification{TxHash: "0x02"})
+	slow(context.Background(), nil, &NewTxNotification{TxHash: "0xab"})
+	require.Len(t, delivered, 4)
+}
+
+func TestRaceFilterSlowCallback(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	filter := newRaceFilter(2, func(ctx context.Context, err error, result any) {
+		if result.(*NewTxNotification).TxHash == "0x01" {
+			close(started)
+			<-release
+		}
+	})
+
+	fast := filter.wrap("fast")
+	slow := filter.wrap("slow")
+
+	go fast(context.Background(), nil, &NewTxNotification{TxHash: "0x01"})
+	<-started
+	defer close(release)
+
+	// a callback busy with the notification of one endpoint doesn't hold up the other endpoints
+	delivered := make(chan struct{})
+	go func() {
+		slow(context.Background(), nil, &NewTxNotification{TxHash: "0x02"})
+		close(delivered)
+	}()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("the notification of the other endpoint is held up by the busy callback")
+	}
+}
+
+func TestNotificationKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0xCbe321c620071307Ba5d0381c886B7359763735E")
+	tx, err := ethtypes.SignTx(ethtypes.NewTx(&ethtypes.LegacyTx{
+		Nonce:    1,
+		To:       &to,
+		Value:    big.NewInt(1),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	}), ethtypes.HomesteadSigner{}, key)
+	require.NoError(t, err)
+
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	hash := tx.Hash().Hex()
+
+	// WS notifications carry a hex-encoded transaction and gRPC notifications a binary one
+	require.Equal(t, hash, notificationKey(&NewTxNotification{TxHash: hash}))
+	require.Equal(t, hash, notificationKey(&NewTxNotification{RawTx: hexutil.Encode(rawTx)}))
+	require.Equal(t, hash, notificationKey(&NewTxNotification{RawTx: string(rawTx)}))
+
+	require.Empty(t, notificationKey(&NewTxNotification{}))
+	require.Empty(t, notificationKey(&OnTxReceiptNotification{}))
+}
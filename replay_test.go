@@ -0,0 +1,121 @@
+package bloxroute_sdk_go
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fastjson"
+
+	"github.com/bloXroute-Labs/bloxroute-sdk-go/connection/ws"
+)
+
+// testNotificationServer starts a websocket server which confirms subscriptions
+// and sends the given number of new transactions to each of them
+func testNotificationServer(t *testing.T, txs int) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			v, err := fastjson.ParseBytes(message)
+			if err != nil || string(v.GetStringBytes("method")) != "subscribe" {
+				continue
+			}
+
+			response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":"server-subscription"}`, v.GetStringBytes("id"))
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(response)); err != nil {
+				return
+			}
+
+			for i := range txs {
+				notification := fmt.Sprintf(`{"jsonrpc":"2.0","method":"subscribe","params":{"subscription":"server-subscription","result":{"rawTx":"0x%02x"}}}`, i)
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(notification)); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// testReceiveTxs subscribes to new transactions and returns the raw transactions of the first n
+func testReceiveTxs(t *testing.T, c *Client, n int) []string {
+	t.Helper()
+
+	txs := make(chan string, n)
+	_, err := c.SubscribeNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+		require.NoError(t, err)
+		txs <- result.RawTx
+	})
+	require.NoError(t, err)
+
+	var received []string
+	for range n {
+		select {
+		case tx := <-txs:
+			received = append(received, tx)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for tx %d", len(received)+1)
+		}
+	}
+
+	return received
+}
+
+func TestRecordReplay(t *testing.T) {
+	recording := &bytes.Buffer{}
+	recorder := ws.NewRecorder(recording)
+
+	c, err := NewClient(context.Background(), &Config{
+		WSGatewayURL: testNotificationServer(t, 3),
+		AuthHeader:   "auth",
+		WrapWSConn:   recorder.Wrap,
+	})
+	require.NoError(t, err)
+
+	recorded := testReceiveTxs(t, c, 3)
+	require.Equal(t, []string{"0x00", "0x01", "0x02"}, recorded)
+	require.NoError(t, c.Close())
+	require.NoError(t, recorder.Err())
+
+	// the recording is replayed through the handler without a server, with new request IDs
+	replay, err := ws.NewReplay(recording, nil)
+	require.NoError(t, err)
+
+	c, err = NewClient(context.Background(), &Config{
+		WSGatewayURL:  "ws://replay.invalid/ws",
+		AuthHeader:    "auth",
+		WSConnectFunc: replay.Dial,
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	require.Equal(t, recorded, testReceiveTxs(t, c, 3))
+
+	select {
+	case <-replay.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the replay to finish")
+	}
+}
@@ -73,11 +73,20 @@ type sendBscBundleParams struct {
 
 // SendBscBundle submits a BSC bundle to the Cloud-API, which validates and forwards the bundle to
 // MEV Relays directly connected to BSC validators participating in our MEV solution program.
-func (c *Client) SendBscBundle(ctx context.Context, params *SendBscBundleParams) (*json.RawMessage, error) {
-	sendBscBundleParams := &sendBscBundleParams{
+func (c *Client) SendBscBundle(ctx context.Context, params *SendBscBundleParams, opts ...CallOption) (*json.RawMessage, error) {
+	return c.request(ctx, c.handler, jsonrpc.RPCBundleSubmission, c.bscBundleParams(params), opts)
+}
+
+// SendBscBundleFanOut submits a BSC bundle through all endpoints in parallel and returns
+// as soon as one of them accepts it. The results of all endpoints are available with FanOutResult.Wait.
+// The endpoints which aren't connected are connected on the first call and stay connected.
+func (c *Client) SendBscBundleFanOut(ctx context.Context, params *SendBscBundleParams, opts ...CallOption) (*FanOutResult, error) {
+	return c.fanOut(ctx, jsonrpc.RPCBundleSubmission, c.bscBundleParams(params), opts)
+}
+
+func (c *Client) bscBundleParams(params *SendBscBundleParams) *sendBscBundleParams {
+	return &sendBscBundleParams{
 		SendBscBundleParams: *params,
 		BlockchainNetwork:   c.blockchainNetwork,
 	}
-
-	return c.handler.Request(ctx, jsonrpc.RPCBundleSubmission, sendBscBundleParams)
 }
@@ -2,7 +2,6 @@ package bloxroute_sdk_go
 
 import (
 	"context"
-	"os"
 	"testing"
 	"time"
 
@@ -11,16 +10,18 @@ import (
 
 func TestSendBscBundle(t *testing.T) {
 	t.Run("ws_cloud_api", testSendBscBundle(wsCloudApiUrl))
-	time.Sleep(5 * time.Second) // give the ws conn time to close
+	if testLive(wsCloudApiUrl) {
+		time.Sleep(5 * time.Second) // give the ws conn time to close
+	}
 	t.Run("ws_gateway", testSendBscBundle(wsGatewayUrl))
+	t.Run("grpc_gateway", testSendBscBundle(grpcGatewayUrl))
 }
 
 func testSendBscBundle(url testURL) func(t *testing.T) {
 	return func(t *testing.T) {
 		config := testConfig(t, url)
 
-		txBytes := os.Getenv("TX_BYTES")
-		require.NotEmpty(t, txBytes)
+		txBytes := testTxBytes(t)
 
 		c, err := NewClient(context.Background(), config)
 		require.NoError(t, err)
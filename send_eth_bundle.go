@@ -54,6 +54,13 @@ type SendEthBundleParams struct {
 
 // SendEthBundle submits a bundle to the Cloud-API or Gateway, which validates and forwards the bundle to MEV relays.
 // Please contact bloXroute support if you have questions regarding the parameters.
-func (c *Client) SendEthBundle(ctx context.Context, params *SendEthBundleParams) (*json.RawMessage, error) {
-	return c.handler.Request(ctx, jsonrpc.RPCBundleSubmission, params)
+func (c *Client) SendEthBundle(ctx context.Context, params *SendEthBundleParams, opts ...CallOption) (*json.RawMessage, error) {
+	return c.request(ctx, c.handler, jsonrpc.RPCBundleSubmission, params, opts)
+}
+
+// SendEthBundleFanOut submits a bundle through all endpoints in parallel and returns
+// as soon as one of them accepts it. The results of all endpoints are available with FanOutResult.Wait.
+// The endpoints which aren't connected are connected on the first call and stay connected.
+func (c *Client) SendEthBundleFanOut(ctx context.Context, params *SendEthBundleParams, opts ...CallOption) (*FanOutResult, error) {
+	return c.fanOut(ctx, jsonrpc.RPCBundleSubmission, params, opts)
 }
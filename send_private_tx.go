@@ -33,10 +33,10 @@ type SendPrivateTxParams struct {
 // Polygon, SendPrivateTx provides server side front-running protection based on the
 // accessibility of the next validator and are eventually sent as semi-private
 // transactions (https://docs.bloxroute.com/apis/frontrunning-protection/bsc_private_tx).
-func (c *Client) SendPrivateTx(ctx context.Context, params *SendPrivateTxParams) (*json.RawMessage, error) {
+func (c *Client) SendPrivateTx(ctx context.Context, params *SendPrivateTxParams, opts ...CallOption) (*json.RawMessage, error) {
 	// error if the user isn't using the cloud API
 	if c.handler.Type() != handlerSourceTypeCloudAPIWS {
-		return nil, fmt.Errorf("SendPrivateTx is only supported on the cloud API")
+		return nil, fmt.Errorf("SendPrivateTx is only supported on the cloud API over WebSocket")
 	}
 
 	if params == nil {
@@ -57,5 +57,5 @@ func (c *Client) SendPrivateTx(ctx context.Context, params *SendPrivateTxParams)
 		}
 	}
 
-	return c.handler.Request(ctx, requestType, params)
+	return c.request(ctx, c.handler, requestType, params, opts)
 }
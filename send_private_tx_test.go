@@ -2,7 +2,6 @@ package bloxroute_sdk_go
 
 import (
 	"context"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,8 +15,7 @@ func testSendPrivateTx(url testURL) func(t *testing.T) {
 	return func(t *testing.T) {
 		config := testConfig(t, url)
 
-		txBytes := os.Getenv("TX_BYTES")
-		require.NotEmpty(t, txBytes)
+		txBytes := testTxBytes(t)
 
 		c, err := NewClient(context.Background(), config)
 		require.NoError(t, err)
@@ -43,7 +43,32 @@ type SendTxParams struct {
 }
 
 // SendTx sends a single transaction faster than the p2p network using the BDN
-func (c *Client) SendTx(ctx context.Context, params *SendTxParams) (*json.RawMessage, error) {
+func (c *Client) SendTx(ctx context.Context, params *SendTxParams, opts ...CallOption) (*json.RawMessage, error) {
+	err := c.prepareSendTx(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.request(ctx, c.handler, jsonrpc.RPCTx, params, opts)
+}
+
+// SendTxFanOut sends a single transaction through all endpoints in parallel and returns
+// as soon as one of them accepts it. The results of all endpoints are available with FanOutResult.Wait.
+// The endpoints which aren't connected are connected on the first call and stay connected.
+func (c *Client) SendTxFanOut(ctx context.Context, params *SendTxParams, opts ...CallOption) (*FanOutResult, error) {
+	err := c.prepareSendTx(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fanOut(ctx, jsonrpc.RPCTx, params, opts)
+}
+
+func (c *Client) prepareSendTx(params *SendTxParams) error {
+	if params == nil {
+		return ErrNilParams
+	}
+
 	// set blockchain network to match the config if not set
 	if params.BlockchainNetwork == "" {
 		params.BlockchainNetwork = c.blockchainNetwork
@@ -51,8 +76,8 @@ func (c *Client) SendTx(ctx context.Context, params *SendTxParams) (*json.RawMes
 
 	// error if the user is using mainnet and next validator
 	if params.BlockchainNetwork == bxgateway.Mainnet && params.NextValidator {
-		return nil, fmt.Errorf("NextValidator is not supported on Ethereum Mainnet")
+		return fmt.Errorf("NextValidator is not supported on Ethereum Mainnet")
 	}
 
-	return c.handler.Request(ctx, jsonrpc.RPCTx, params)
+	return nil
 }
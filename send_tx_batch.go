@@ -0,0 +1,124 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	bxgateway "github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+)
+
+var (
+	ErrEmptyTxBatch = errors.New("transaction batch is empty")
+	ErrNoTxResult   = errors.New("no result for the transaction in the batch reply")
+)
+
+// SendTxBatchParams are the parameters for sending several transactions in one request
+type SendTxBatchParams struct {
+	// The hex-encoded bytes of the transactions (without 0x prefix)
+	Transactions []string `json:"transactions"`
+
+	// BlockchainNetwork is the blockchain network to send the transactions to.
+	// Optional (defaults to the network of the client)
+	BlockchainNetwork string `json:"blockchain_network,omitempty"`
+
+	// A boolean flag indicating if Tx Nonce Monitoring should be
+	// enabled for the transactions.
+	NonceMonitoring bool `json:"nonce_monitoring,omitempty"`
+
+	// A boolean flag used to send the transactions only to validators
+	// accessible via the BDN. Available only for Ethereum, BSC & Polygon
+	ValidatorsOnly bool `json:"validators_only,omitempty"`
+
+	// A boolean flag used to send the transactions only to the validators
+	// that are next-in-turn, if they are accessible via the BDN. Available
+	// only for BSC & Polygon
+	NextValidator bool `json:"next_validator,omitempty"`
+
+	// When using next_validator, fall_back is the duration of time (in ms)
+	// that the transactions will be delayed before propagation by the
+	// BDN as normal transactions. Default is 0, which indicates no fallback.
+	Fallback uint `json:"fallback,omitempty"`
+}
+
+// TxBatchResult is the outcome of a single transaction of a batch
+type TxBatchResult struct {
+	// Transaction is the transaction as it was passed in SendTxBatchParams
+	Transaction string
+
+	// TxHash is the hash of the transaction when it was accepted
+	TxHash string
+
+	// Accepted is set when the transaction was accepted
+	Accepted bool
+
+	// Err is why the transaction was rejected
+	Err error
+}
+
+// SendTxBatch sends several transactions in a single request using the BDN. It returns the
+// result of each transaction, in the order of params.Transactions. The error is set only when
+// the batch as a whole failed.
+func (c *Client) SendTxBatch(ctx context.Context, params *SendTxBatchParams, opts ...CallOption) ([]TxBatchResult, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+
+	if len(params.Transactions) == 0 {
+		return nil, ErrEmptyTxBatch
+	}
+
+	// set blockchain network to match the config if not set
+	if params.BlockchainNetwork == "" {
+		params.BlockchainNetwork = c.blockchainNetwork
+	}
+
+	// error if the user is using mainnet and next validator
+	if params.BlockchainNetwork == bxgateway.Mainnet && params.NextValidator {
+		return nil, fmt.Errorf("NextValidator is not supported on Ethereum Mainnet")
+	}
+
+	res, err := c.request(ctx, c.handler, jsonrpc.RPCBatchTx, params, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply SendTxBatchReply
+	err = json.Unmarshal(*res, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch reply: %w", err)
+	}
+
+	return txBatchResults(params.Transactions, &reply), nil
+}
+
+// txBatchResults matches the hashes and errors of the reply with the transactions of the batch
+func txBatchResults(transactions []string, reply *SendTxBatchReply) []TxBatchResult {
+	results := make([]TxBatchResult, len(transactions))
+	for i, tx := range transactions {
+		results[i] = TxBatchResult{Transaction: tx, Err: ErrNoTxResult}
+	}
+
+	for _, txHash := range reply.TxHashes {
+		if txHash.Index < 0 || txHash.Index >= len(results) {
+			continue
+		}
+
+		results[txHash.Index].TxHash = txHash.TxHash
+		results[txHash.Index].Accepted = true
+		results[txHash.Index].Err = nil
+	}
+
+	for _, txErr := range reply.TxErrors {
+		if txErr.Index < 0 || txErr.Index >= len(results) {
+			continue
+		}
+
+		results[txErr.Index].Accepted = false
+		results[txErr.Index].Err = errors.New(txErr.Error)
+	}
+
+	return results
+}
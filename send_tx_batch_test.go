@@ -0,0 +1,52 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTxBatch(t *testing.T) {
+	t.Run("ws_cloud_api", testSendTxBatch(wsCloudApiUrl))
+	t.Run("ws_gateway", testSendTxBatch(wsGatewayUrl))
+	t.Run("grpc_gateway", testSendTxBatch(grpcGatewayUrl))
+}
+
+func testSendTxBatch(url testURL) func(t *testing.T) {
+	return func(t *testing.T) {
+		config := testConfig(t, url)
+
+		txBytes := testTxBytes(t)
+
+		c, err := NewClient(context.Background(), config)
+		require.NoError(t, err)
+
+		results, err := c.SendTxBatch(context.Background(), &SendTxBatchParams{
+			Transactions: []string{txBytes},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, txBytes, results[0].Transaction)
+
+		require.NoError(t, c.Close())
+	}
+}
+
+func TestTxBatchResults(t *testing.T) {
+	results := txBatchResults([]string{"a", "b", "c"}, &SendTxBatchReply{
+		TxHashes: []BatchTxHash{{Index: 0, TxHash: "0x1"}, {Index: 5, TxHash: "0x5"}},
+		TxErrors: []BatchTxError{{Index: 1, Error: "nonce too low"}},
+	})
+
+	require.Len(t, results, 3)
+
+	require.Equal(t, TxBatchResult{Transaction: "a", TxHash: "0x1", Accepted: true}, results[0])
+
+	require.False(t, results[1].Accepted)
+	require.EqualError(t, results[1].Err, "nonce too low")
+
+	// a transaction missing from the reply is neither accepted nor rejected by the gateway
+	require.False(t, results[2].Accepted)
+	require.ErrorIs(t, results[2].Err, ErrNoTxResult)
+}
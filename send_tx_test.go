@@ -2,7 +2,6 @@ package bloxroute_sdk_go
 
 import (
 	"context"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,6 +10,7 @@ import (
 func TestSendTx(t *testing.T) {
 	t.Run("ws_cloud_api", testSendTx(wsCloudApiUrl))
 	t.Run("ws_gateway", testSendTx(wsGatewayUrl))
+	t.Run("grpc_cloud_api", testSendTx(grpcCloudApiUrl))
 	t.Run("grpc_gateway", testSendTx(grpcGatewayUrl))
 }
 
@@ -18,9 +18,7 @@ func testSendTx(url testURL) func(t *testing.T) {
 	return func(t *testing.T) {
 		config := testConfig(t, url)
 
-		// get tx bytes from os env and error if not found
-		txBytes := os.Getenv("TX_BYTES")
-		require.NotEmpty(t, txBytes)
+		txBytes := testTxBytes(t)
 
 		c, err := NewClient(context.Background(), config)
 		require.NoError(t, err)
@@ -0,0 +1,117 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"sync"
+)
+
+// Notification is a single result of a subscription delivered through a channel.
+// Err is set instead of Result when the subscription failed to deliver a notification.
+type Notification[T any] struct {
+	Result T
+	Err    error
+}
+
+// stream passes the notifications of a subscription to a channel
+// and ends the subscription when its context is canceled
+type stream[T any] struct {
+	ch     chan Notification[T]
+	closed bool
+	lock   *sync.RWMutex
+	// done is closed after the subscription is ended and ch is closed
+	done chan struct{}
+}
+
+// subscribeStream subscribes with the given function and returns a stream of its notifications.
+// The subscription is ended and the channel is closed when ctx is canceled
+// or when the subscription is ended because of ErrSubscriptionOverflow.
+func subscribeStream[T any](ctx context.Context, logger *slog.Logger, subscribe func(context.Context, CallbackFunc[T]) (*Subscription, error)) (*stream[T], error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &stream[T]{
+		ch:   make(chan Notification[T]),
+		lock: &sync.RWMutex{},
+		done: make(chan struct{}),
+	}
+
+	sub, err := subscribe(ctx, func(_ context.Context, err error, result T) {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+
+		if s.closed {
+			return
+		}
+
+		select {
+		case s.ch <- Notification[T]{Result: result, Err: err}:
+		case <-ctx.Done():
+		}
+
+		if errors.Is(err, ErrSubscriptionOverflow) {
+			// the subscription is already ended, so just close the channel
+			cancel()
+		}
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := sub.Unsubscribe(); err != nil {
+			logger.Debug("failed to unsubscribe stream",
+				slog.String(logKeyFeed, string(sub.Feed())), slog.String(logKeySubscription, sub.ID()), slog.Any(logKeyError, err))
+		}
+
+		// wait until no callback is sending before closing the channel
+		s.lock.Lock()
+		s.closed = true
+		close(s.ch)
+		s.lock.Unlock()
+
+		close(s.done)
+	}()
+
+	return s, nil
+}
+
+// subscribeChan returns the channel of a stream subscribed with the given function
+func subscribeChan[T any](ctx context.Context, c *Client, subscribe func(context.Context, CallbackFunc[T]) (*Subscription, error)) (<-chan Notification[T], error) {
+	s, err := subscribeStream(ctx, c.config.Slog, subscribe)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ch, nil
+}
+
+// subscribeSeq returns an iterator which subscribes with the given function when the loop starts
+// and unsubscribes when the loop ends or ctx is canceled. A failed subscription is yielded as an error.
+func subscribeSeq[T any](ctx context.Context, c *Client, subscribe func(context.Context, CallbackFunc[T]) (*Subscription, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		s, err := subscribeStream(ctx, c.config.Slog, subscribe)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		for n := range s.ch {
+			if !yield(n.Result, n.Err) {
+				break
+			}
+		}
+
+		// end the subscription before returning
+		cancel()
+		<-s.done
+	}
+}
@@ -0,0 +1,149 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testFeed is a feed which delivers the notifications written to it until it is unsubscribed
+type testFeed struct {
+	callback     CallbackFunc[int]
+	subscribed   chan struct{}
+	unsubscribed atomic.Int32
+}
+
+func newTestFeed() *testFeed {
+	return &testFeed{subscribed: make(chan struct{})}
+}
+
+func (f *testFeed) subscribe(_ context.Context, callback CallbackFunc[int]) (*Subscription, error) {
+	f.callback = callback
+	close(f.subscribed)
+	return &Subscription{unsubscribe: f.unsubscribe}, nil
+}
+
+func (f *testFeed) unsubscribe() error {
+	f.unsubscribed.Add(1)
+	return nil
+}
+
+func (f *testFeed) publish(count int) {
+	<-f.subscribed
+	for i := 0; i < count; i++ {
+		f.callback(context.Background(), nil, i)
+	}
+}
+
+func testStreamClient() *Client {
+	return &Client{config: &Config{Logger: &NoopLogger{}, Slog: slog.New(NewLoggerHandler(&NoopLogger{}))}}
+}
+
+func TestSubscribeChan(t *testing.T) {
+	f := newTestFeed()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := subscribeChan(ctx, testStreamClient(), f.subscribe)
+	require.NoError(t, err)
+
+	go f.publish(3)
+
+	for i := 0; i < 3; i++ {
+		n := <-ch
+		require.NoError(t, n.Err)
+		require.Equal(t, i, n.Result)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel wasn't closed after the context was canceled")
+	}
+
+	require.Equal(t, int32(1), f.unsubscribed.Load())
+
+	// notifications which arrive after the channel is closed are dropped
+	f.callback(context.Background(), nil, 4)
+}
+
+func TestSubscribeChanOverflow(t *testing.T) {
+	f := newTestFeed()
+
+	ch, err := subscribeChan(context.Background(), testStreamClient(), f.subscribe)
+	require.NoError(t, err)
+
+	go func() {
+		<-f.subscribed
+		f.callback(context.Background(), ErrSubscriptionOverflow, 0)
+	}()
+
+	n := <-ch
+	require.ErrorIs(t, n.Err, ErrSubscriptionOverflow)
+
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel wasn't closed after the subscription overflowed")
+	}
+}
+
+func TestSubscribeSeq(t *testing.T) {
+	t.Run("break", func(t *testing.T) {
+		f := newTestFeed()
+
+		go f.publish(10)
+
+		var received []int
+		for result, err := range subscribeSeq(context.Background(), testStreamClient(), f.subscribe) {
+			require.NoError(t, err)
+			received = append(received, result)
+			if len(received) == 2 {
+				break
+			}
+		}
+
+		require.Equal(t, []int{0, 1}, received)
+		// the subscription is ended by the time the loop returns
+		require.Equal(t, int32(1), f.unsubscribed.Load())
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		f := newTestFeed()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go func() {
+			f.publish(1)
+			cancel()
+		}()
+
+		var received int
+		for _, err := range subscribeSeq(ctx, testStreamClient(), f.subscribe) {
+			require.NoError(t, err)
+			received++
+		}
+
+		require.Equal(t, 1, received)
+		require.Equal(t, int32(1), f.unsubscribed.Load())
+	})
+
+	t.Run("subscribe_error", func(t *testing.T) {
+		subscribeErr := errors.New("subscribe failed")
+		subscribe := func(context.Context, CallbackFunc[int]) (*Subscription, error) { return nil, subscribeErr }
+
+		var errs []error
+		for _, err := range subscribeSeq(context.Background(), testStreamClient(), subscribe) {
+			errs = append(errs, err)
+		}
+
+		require.Equal(t, []error{subscribeErr}, errs)
+	})
+}
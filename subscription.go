@@ -0,0 +1,76 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// Subscription is a handle to a single feed subscription.
+// Several subscriptions to the same feed, each with its own params and callback, can be active at once.
+type Subscription struct {
+	id          string
+	feed        types.FeedType
+	unsubscribe func() error
+}
+
+// ID returns the ID of the subscription assigned by the SDK.
+// It stays the same when the subscription is renewed after a reconnect or a failover.
+func (s *Subscription) ID() string {
+	return s.id
+}
+
+// Feed returns the feed of the subscription
+func (s *Subscription) Feed() types.FeedType {
+	return s.feed
+}
+
+// Unsubscribe ends the subscription without affecting other subscriptions to the same feed
+func (s *Subscription) Unsubscribe() error {
+	return s.unsubscribe()
+}
+
+// subscriptionID returns the ID of the subscription, or an empty ID if there is no subscription
+func (s *Subscription) subscriptionID() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.id
+}
+
+// subscribe makes a new subscription to the feed and returns its handle
+func (c *Client) subscribe(ctx context.Context, f types.FeedType, params any, callback CallbackFunc[any], opts []CallOption) (_ *Subscription, err error) {
+	id := randomID().Str
+
+	ctx, span := c.startSpan(ctx, jsonrpc.RPCSubscribe, attrFeed.String(string(f)), attrSubscription.String(id))
+	defer func() { endSpan(span, err) }()
+
+	// the notifications are linked to the span of the subscription rather than being its children,
+	// as the subscription lasts much longer than its span
+	callback = c.traceNotifications(span.SpanContext(), id, f, callback)
+
+	ctx, o := c.withCallOptions(ctx, opts)
+	limitCtx, cancel := o.withDeadline(ctx)
+	err = c.limiter.subscribe(limitCtx)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.do(ctx, func() error {
+		return c.handler.Subscribe(ctx, id, f, params, callback)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		id:   id,
+		feed: f,
+		unsubscribe: func() error {
+			return c.handler.Unsubscribe(id)
+		},
+	}, nil
+}
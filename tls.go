@@ -0,0 +1,113 @@
+package bloxroute_sdk_go
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPin    = errors.New("pin must be a base64-encoded SHA-256 hash")
+	ErrPinMismatch   = errors.New("no certificate of the endpoint matches the pinned public keys")
+	errNoCertificate = errors.New("endpoint presented no certificate")
+)
+
+// TLSConfig is the TLS configuration of the connections to a kind of endpoint. It applies to
+// wss:// URLs, grpcs:// URLs and gRPC URLs without a scheme. The certificates of the endpoints
+// are verified against the system roots by default.
+type TLSConfig struct {
+	// InsecureSkipVerify disables the verification of the certificates of the endpoints. The pins,
+	// if any, are still checked against the certificates the endpoints present.
+	// Optional (default: false)
+	InsecureSkipVerify bool
+
+	// RootCAs verifies the certificates of the endpoints, e.g. of self-hosted gateways with a private CA
+	// Optional (default: the system roots)
+	RootCAs *x509.CertPool
+
+	// PinnedSPKIHashes are the base64-encoded SHA-256 hashes of the Subject Public Key Info of the
+	// certificates the endpoints may present, as printed by
+	// `openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64`.
+	// The connection is refused unless a certificate of the chain matches one of them.
+	// Optional
+	PinnedSPKIHashes []string
+
+	// Certificates are presented to the endpoints which ask for a client certificate (mTLS).
+	// Use tls.LoadX509KeyPair to load one from files.
+	// Optional
+	Certificates []tls.Certificate
+}
+
+func (t *TLSConfig) validate() error {
+	if t == nil {
+		return nil
+	}
+
+	for _, pin := range t.PinnedSPKIHashes {
+		hash, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("%w: %s", ErrInvalidPin, pin)
+		}
+	}
+
+	return nil
+}
+
+// clientConfig returns the crypto/tls configuration of the connections
+func (t *TLSConfig) clientConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t == nil {
+		return config
+	}
+
+	config.InsecureSkipVerify = t.InsecureSkipVerify
+	config.RootCAs = t.RootCAs
+	config.Certificates = t.Certificates
+
+	if len(t.PinnedSPKIHashes) > 0 {
+		pins := make([][]byte, len(t.PinnedSPKIHashes))
+		for i, pin := range t.PinnedSPKIHashes {
+			// the pins are checked by validate
+			pins[i], _ = base64.StdEncoding.DecodeString(pin)
+		}
+
+		// VerifyConnection is called after the chain is verified, and also when the verification is skipped
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			return verifyPins(cs, pins)
+		}
+	}
+
+	return config
+}
+
+// verifyPins checks that a certificate of the verified chains has one of the pinned public keys. If the
+// verification is skipped, only the leaf certificate counts as the endpoint proved it holds its key.
+func verifyPins(cs tls.ConnectionState, pins [][]byte) error {
+	var certificates []*x509.Certificate
+	for _, chain := range cs.VerifiedChains {
+		certificates = append(certificates, chain...)
+	}
+
+	if len(cs.VerifiedChains) == 0 && len(cs.PeerCertificates) > 0 {
+		certificates = cs.PeerCertificates[:1]
+	}
+
+	if len(certificates) == 0 {
+		return errNoCertificate
+	}
+
+	for _, certificate := range certificates {
+		hash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+		for _, pin := range pins {
+			if bytes.Equal(hash[:], pin) {
+				return nil
+			}
+		}
+	}
+
+	return ErrPinMismatch
+}
@@ -0,0 +1,213 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+// testCertificate creates a self-signed certificate for 127.0.0.1, the pool which trusts it and its SPKI pin
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+
+	pin := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool, base64.StdEncoding.EncodeToString(pin[:])
+}
+
+// testTLSServer starts a wss server which accepts connections and ignores their messages
+func testTLSServer(t *testing.T, config *tls.Config) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return "wss" + strings.TrimPrefix(server.URL, "https")
+}
+
+func TestTLS(t *testing.T) {
+	serverCertificate, serverPool, serverPin := testCertificate(t)
+	clientCertificate, clientPool, _ := testCertificate(t)
+	_, _, otherPin := testCertificate(t)
+
+	t.Run("ws", func(t *testing.T) {
+		url := testTLSServer(t, &tls.Config{Certificates: []tls.Certificate{serverCertificate}})
+
+		tests := []struct {
+			name string
+			tls  *TLSConfig
+			err  bool
+		}{
+			{name: "unknown_authority", err: true},
+			{name: "root_ca", tls: &TLSConfig{RootCAs: serverPool}},
+			{name: "insecure_skip_verify", tls: &TLSConfig{InsecureSkipVerify: true}},
+			{name: "pin", tls: &TLSConfig{RootCAs: serverPool, PinnedSPKIHashes: []string{otherPin, serverPin}}},
+			{name: "pin_mismatch", tls: &TLSConfig{RootCAs: serverPool, PinnedSPKIHashes: []string{otherPin}}, err: true},
+			{name: "pin_insecure_skip_verify", tls: &TLSConfig{InsecureSkipVerify: true, PinnedSPKIHashes: []string{otherPin}}, err: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, err := NewClient(context.Background(), &Config{
+					WSCloudAPIURL:    url,
+					AuthHeader:       "auth",
+					CloudAPITLS:      tt.tls,
+					ReconnectTimeout: 100 * time.Millisecond,
+				})
+				if tt.err {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				require.NoError(t, c.Close())
+			})
+		}
+	})
+
+	t.Run("grpc_mtls", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{serverCertificate},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientPool,
+		})))
+		pb.RegisterGatewayServer(server, &testGatewayServer{})
+		go func() { _ = server.Serve(listener) }()
+		t.Cleanup(server.Stop)
+
+		send := func(t *testing.T, config *TLSConfig) error {
+			c, err := NewClient(context.Background(), &Config{
+				GRPCGatewayURL: "grpcs://" + listener.Addr().String(),
+				AuthHeader:     "auth",
+				GatewayTLS:     config,
+			})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, c.Close()) }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			_, err = c.SendTx(ctx, &SendTxParams{Transaction: "f86b"})
+			return err
+		}
+
+		require.Error(t, send(t, &TLSConfig{RootCAs: serverPool}))
+		require.NoError(t, send(t, &TLSConfig{RootCAs: serverPool, Certificates: []tls.Certificate{clientCertificate}}))
+	})
+
+	t.Run("grpc_insecure", func(t *testing.T) {
+		url := testGRPCGateway(t, &testGatewayServer{})
+
+		tests := []struct {
+			name   string
+			config Config
+			err    bool
+		}{
+			{name: "scheme", config: Config{GRPCGatewayURL: url}},
+			{name: "setting", config: Config{GRPCGatewayURL: strings.TrimPrefix(url, "grpc://"), GRPCInsecure: true}},
+			// the authorization isn't sent in clear without an explicit opt-out
+			{name: "dial_options", err: true, config: Config{
+				GRPCGatewayURL:  strings.TrimPrefix(url, "grpc://"),
+				GRPCDialOptions: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				config := tt.config
+				config.AuthHeader = "auth"
+				c, err := NewClient(context.Background(), &config)
+				if tt.err {
+					// grpc refuses credentials which require TLS over a plain text transport
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				defer func() { require.NoError(t, c.Close()) }()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+
+				_, err = c.SendTx(ctx, &SendTxParams{Transaction: "f86b"})
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("grpc_credentials", func(t *testing.T) {
+		require.True(t, grpcCredentials{}.RequireTransportSecurity())
+		require.False(t, grpcCredentials{insecure: true}.RequireTransportSecurity())
+	})
+
+	t.Run("invalid_pin", func(t *testing.T) {
+		_, err := NewClient(context.Background(), &Config{
+			WSCloudAPIURL: "wss://127.0.0.1:1",
+			AuthHeader:    "auth",
+			CloudAPITLS:   &TLSConfig{PinnedSPKIHashes: []string{"not a pin"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidPin)
+	})
+}
@@ -0,0 +1,118 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const tracerName = "github.com/bloXroute-Labs/bloxroute-sdk-go"
+
+// span attribute keys
+const (
+	attrMethod       = attribute.Key("rpc.method")
+	attrRequestID    = attribute.Key("rpc.jsonrpc.request_id")
+	attrNetwork      = attribute.Key("bloxroute.network")
+	attrEndpoint     = attribute.Key("bloxroute.endpoint")
+	attrHandler      = attribute.Key("bloxroute.handler")
+	attrFeed         = attribute.Key("bloxroute.feed")
+	attrSubscription = attribute.Key("bloxroute.subscription")
+)
+
+// tracer returns the tracer of TracerProvider, or of the global provider if none is set
+func (c *Config) tracer() trace.Tracer {
+	if c.TracerProvider == nil {
+		return otel.GetTracerProvider().Tracer(tracerName, trace.WithInstrumentationVersion(buildVersion))
+	}
+
+	return c.TracerProvider.Tracer(tracerName, trace.WithInstrumentationVersion(buildVersion))
+}
+
+// propagator returns Propagator, or the global propagator if none is set
+func (c *Config) propagator() propagation.TextMapPropagator {
+	if c.Propagator == nil {
+		return otel.GetTextMapPropagator()
+	}
+
+	return c.Propagator
+}
+
+// startSpan starts the span of a request of the method. The handler which sends
+// the request adds its endpoint and type, and the JSON-RPC ID of a WS request.
+func (c *Client) startSpan(ctx context.Context, method jsonrpc.RPCRequestType, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.config.tracer().Start(ctx, string(method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrMethod.String(string(method)), attrNetwork.String(c.blockchainNetwork)),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records the error of the call, if any, and ends its span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// traceNotifications wraps the callback of a subscription so that each notification is passed to it
+// with a span of its own, linked to the span of the subscription, which may have ended long before
+func (c *Client) traceNotifications(subscription trace.SpanContext, id string, f types.FeedType, callback CallbackFunc[any]) CallbackFunc[any] {
+	tracer := c.config.tracer()
+	name := string(f) + " notification"
+
+	return func(ctx context.Context, err error, result any) {
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithNewRoot(),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithLinks(trace.Link{SpanContext: subscription}),
+			trace.WithAttributes(attrFeed.String(string(f)), attrSubscription.String(id)),
+		)
+		defer func() { endSpan(span, err) }()
+
+		callback(ctx, err, result)
+	}
+}
+
+// metadataCarrier injects the trace context into the gRPC metadata
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	values := metadata.MD(m).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// outgoingContext returns the context of a gRPC call with the metadata and the trace context of ctx
+func (h *grpcHandler) outgoingContext(ctx context.Context) context.Context {
+	md := h.md.Copy()
+	h.config.propagator().Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
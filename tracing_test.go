@@ -0,0 +1,161 @@
+package bloxroute_sdk_go
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// testTraceGatewayServer records the trace context of each transaction request
+type testTraceGatewayServer struct {
+	testGatewayServer
+	traceparent atomic.Value
+}
+
+func (s *testTraceGatewayServer) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("traceparent"); len(values) > 0 {
+		s.traceparent.Store(values[0])
+	}
+
+	return s.testGatewayServer.BlxrTx(ctx, req)
+}
+
+// testSpan returns the single ended span with the name
+func testSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	var found []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			found = append(found, span)
+		}
+	}
+	require.Len(t, found, 1, name)
+
+	return found[0]
+}
+
+// testSpanAttribute returns the value of the attribute of the span
+func testSpanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+func TestTracing(t *testing.T) {
+	t.Run("grpc", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		srv := &testTraceGatewayServer{}
+		url := testGRPCGateway(t, srv)
+
+		c, err := NewClient(context.Background(), &Config{
+			GRPCGatewayURL: url,
+			AuthHeader:     "auth",
+			TracerProvider: provider,
+			Propagator:     propagation.TraceContext{},
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, c.Close()) }()
+
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "strategy")
+		_, err = c.SendTx(ctx, &SendTxParams{Transaction: "f86b"})
+		require.NoError(t, err)
+		parent.End()
+
+		span := testSpan(t, recorder, string(jsonrpc.RPCTx))
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		require.Equal(t, trace.SpanKindClient, span.SpanKind())
+		require.Equal(t, string(jsonrpc.RPCTx), testSpanAttribute(span, attrMethod))
+		require.Equal(t, url, testSpanAttribute(span, attrEndpoint))
+		require.Equal(t, handlerSourceTypeGatewayGRPC.String(), testSpanAttribute(span, attrHandler))
+		require.Equal(t, c.config.BlockchainNetwork, testSpanAttribute(span, attrNetwork))
+
+		// the gateway receives the trace context of the request span next to the blockchain header
+		traceparent, _ := srv.traceparent.Load().(string)
+		require.Contains(t, traceparent, span.SpanContext().TraceID().String())
+		require.Contains(t, traceparent, span.SpanContext().SpanID().String())
+
+		txs := make(chan trace.SpanContext, 10)
+		sub, err := c.SubscribeNewTx(context.Background(), nil, func(ctx context.Context, err error, result *NewTxNotification) {
+			txs <- trace.SpanContextFromContext(ctx)
+		})
+		require.NoError(t, err)
+
+		var notification trace.SpanContext
+		select {
+		case notification = <-txs:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for tx")
+		}
+		require.True(t, notification.IsValid())
+
+		subscribe := testSpan(t, recorder, string(jsonrpc.RPCSubscribe))
+		require.Equal(t, sub.ID(), testSpanAttribute(subscribe, attrSubscription))
+		require.Equal(t, string(types.NewTxsFeed), testSpanAttribute(subscribe, attrFeed))
+
+		// each notification starts a trace of its own, linked to the subscription
+		require.Eventually(t, func() bool {
+			for _, span := range recorder.Ended() {
+				if span.SpanContext().SpanID() == notification.SpanID() {
+					return len(span.Links()) == 1 && span.Links()[0].SpanContext.SpanID() == subscribe.SpanContext().SpanID()
+				}
+			}
+			return false
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NotEqual(t, subscribe.SpanContext().TraceID(), notification.TraceID())
+	})
+
+	t.Run("ws", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		url := testEventsServer(t)
+		resubscribed := make(chan struct{}, 1)
+
+		c, err := NewClient(context.Background(), &Config{
+			WSGatewayURL:   url,
+			AuthHeader:     "auth",
+			TracerProvider: provider,
+			OnEvent: func(e Event) {
+				if e.Type == EventResubscribed {
+					resubscribed <- struct{}{}
+				}
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = c.SubscribeNewTx(context.Background(), nil, func(context.Context, error, *NewTxNotification) {})
+		require.NoError(t, err)
+
+		span := testSpan(t, recorder, string(jsonrpc.RPCSubscribe))
+		require.Equal(t, url, testSpanAttribute(span, attrEndpoint))
+		require.Equal(t, handlerSourceTypeGatewayWS.String(), testSpanAttribute(span, attrHandler))
+		require.NotEmpty(t, testSpanAttribute(span, attrRequestID))
+
+		// the server drops the first connection right after the subscription
+		select {
+		case <-resubscribed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the resubscribed event")
+		}
+		require.NoError(t, c.Close())
+	})
+}
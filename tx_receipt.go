@@ -2,6 +2,7 @@ package bloxroute_sdk_go
 
 import (
 	"context"
+	"iter"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
 )
@@ -17,7 +18,14 @@ type TxReceiptParams struct {
 }
 
 // OnTxReceipt subscribes to all transaction receipts in each newly mined block.
-func (c *Client) OnTxReceipt(ctx context.Context, params *TxReceiptParams, callbackFunc CallbackFunc[*OnTxReceiptNotification]) error {
+func (c *Client) OnTxReceipt(ctx context.Context, params *TxReceiptParams, callbackFunc CallbackFunc[*OnTxReceiptNotification], opts ...CallOption) error {
+	_, err := c.SubscribeTxReceipt(ctx, params, callbackFunc, opts...)
+	return err
+}
+
+// SubscribeTxReceipt subscribes to the tx receipts feed and returns the handle of the subscription,
+// which can be ended independently of other subscriptions to the same feed
+func (c *Client) SubscribeTxReceipt(ctx context.Context, params *TxReceiptParams, callbackFunc CallbackFunc[*OnTxReceiptNotification], opts ...CallOption) (*Subscription, error) {
 	if params == nil {
 		params = &TxReceiptParams{}
 	}
@@ -35,10 +43,26 @@ func (c *Client) OnTxReceipt(ctx context.Context, params *TxReceiptParams, callb
 		callbackFunc(ctx, err, result.(*OnTxReceiptNotification))
 	}
 
-	return c.handler.Subscribe(ctx, types.TxReceiptsFeed, params, wrap)
+	return c.subscribe(ctx, types.TxReceiptsFeed, params, wrap, opts)
+}
+
+// OnTxReceiptChan subscribes to the tx receipts feed and returns a channel of its notifications.
+// The subscription ends and the channel is closed when ctx is canceled.
+func (c *Client) OnTxReceiptChan(ctx context.Context, params *TxReceiptParams, opts ...CallOption) (<-chan Notification[*OnTxReceiptNotification], error) {
+	return subscribeChan(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnTxReceiptNotification]) (*Subscription, error) {
+		return c.SubscribeTxReceipt(ctx, params, callback, opts...)
+	})
+}
+
+// OnTxReceiptSeq returns an iterator over the notifications of the tx receipts feed.
+// The subscription is made when the loop starts and ends when the loop ends or ctx is canceled.
+func (c *Client) OnTxReceiptSeq(ctx context.Context, params *TxReceiptParams, opts ...CallOption) iter.Seq2[*OnTxReceiptNotification, error] {
+	return subscribeSeq(ctx, c, func(ctx context.Context, callback CallbackFunc[*OnTxReceiptNotification]) (*Subscription, error) {
+		return c.SubscribeTxReceipt(ctx, params, callback, opts...)
+	})
 }
 
-// UnsubscribeFromTxReceipts unsubscribes from the tx receipts feed.
+// UnsubscribeFromTxReceipts ends all subscriptions to the tx receipts feed.
 func (c *Client) UnsubscribeFromTxReceipts() error {
 	return c.handler.UnsubscribeRetry(types.TxReceiptsFeed)
 }
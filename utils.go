@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/sourcegraph/jsonrpc2"
 
 	"github.com/bloXroute-Labs/bloxroute-sdk-go/connection/ws"
@@ -18,24 +21,33 @@ import (
 //go:embed version.txt
 var buildVersion string
 
-func reconnect(ctx context.Context, url string, headers http.Header, opts *ws.DialOptions) (ws.Conn, error) {
+// reconnect dials the WS endpoint until it succeeds or timeout elapses. The auth header is
+// taken from auth before each attempt, so that a rotated secret is used by the next one.
+func reconnect(ctx context.Context, url string, headers http.Header, opts *ws.DialOptions, timeout time.Duration, auth AuthProvider) (ws.Conn, error) {
 	backOff := backoff.NewExponentialBackOff()
-	backOff.MaxElapsedTime = reconnectTimeout
+	backOff.MaxElapsedTime = timeout
 	backOff.InitialInterval = reconnectInitialInterval
 
 	var conn ws.Conn
 
 	fn := func() error {
-		var err error
+		authHeader, err := auth.AuthHeader(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get auth header: %w", err)
+		}
+
+		headers = headers.Clone()
+		headers.Set(authHeaderKey, authHeader)
+
 		conn, err = ws.Dial(ctx, url, headers, opts)
 		if err != nil {
-			return fmt.Errorf("failed to reconnect to cloud API after %s: %w", reconnectTimeout, err)
+			return fmt.Errorf("failed to reconnect to cloud API after %s: %w", timeout, err)
 		}
 
 		return nil
 	}
 
-	err := backoff.Retry(fn, backOff)
+	err := backoff.Retry(fn, backoff.WithContext(backOff, ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -56,3 +68,14 @@ func randomID() jsonrpc2.ID {
 
 	return jsonrpc2.ID{Str: idStr, IsString: true}
 }
+
+// txSender recovers the sender of a hex-encoded signed transaction
+func txSender(rawTx string) (common.Address, error) {
+	var tx ethtypes.Transaction
+	err := tx.UnmarshalBinary(common.FromHex(rawTx))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+
+	return ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), &tx)
+}